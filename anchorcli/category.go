@@ -165,3 +165,20 @@ var CategoryDiagnostic = &Category{
 	Description: "lcl.host Diagnostic System",
 	Glyph:       "code",
 }
+
+var Categories = []*Category{
+	CategoryCustom,
+	CategoryGo,
+	CategoryJavascript,
+	CategoryPython,
+	CategoryRuby,
+	CategoryApache,
+	CategoryCaddy,
+	CategoryNginx,
+	CategoryMonogoDB,
+	CategoryMySQL,
+	CategoryPostgreSQL,
+	CategoryLocalhost,
+	CategoryDebian,
+	CategoryDiagnostic,
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/anchordotdev/cli/toml"
+)
+
+// Prompt identifiers, the keys of an answers file.
+const (
+	AnswerOrg              = "org"
+	AnswerOrgName          = "org-name"
+	AnswerRealm            = "realm"
+	AnswerService          = "service"
+	AnswerServiceName      = "service-name"
+	AnswerCategory         = "category"
+	AnswerDomain           = "domain"
+	AnswerDiagnosticDomain = "diagnostic-domain"
+	AnswerCertStyle        = "cert-style"
+	AnswerIntegration      = "integration"
+	AnswerEnvOutput        = "env-output"
+)
+
+var answerIDs = []string{
+	AnswerOrg,
+	AnswerOrgName,
+	AnswerRealm,
+	AnswerService,
+	AnswerServiceName,
+	AnswerCategory,
+	AnswerDomain,
+	AnswerDiagnosticDomain,
+	AnswerCertStyle,
+	AnswerIntegration,
+	AnswerEnvOutput,
+}
+
+// answerEnums map the prompts answered with a config value to its key in
+// ConfigEnums.
+var answerEnums = map[string]string{
+	AnswerCategory:  "service.category",
+	AnswerCertStyle: "service.cert-style",
+	AnswerEnvOutput: "service.env-output",
+}
+
+// AnswerNew answers an org or service selection by creating a new one.
+const AnswerNew = "+new"
+
+// Answers map prompt identifiers to predetermined answers, for scripting
+// commands that otherwise prompt.
+type Answers map[string]string
+
+// LoadAnswers reads the answers TOML file at path.
+func LoadAnswers(path string) (Answers, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, UserError{Err: fmt.Errorf("reading answers file: %w", err)}
+	}
+
+	var answers Answers
+	if err := toml.Unmarshal(data, &answers); err != nil {
+		return nil, UserError{Err: fmt.Errorf("parsing answers file %s: %w", path, err)}
+	}
+
+	for id, answer := range answers {
+		if !slices.Contains(answerIDs, id) {
+			return nil, UserError{Err: fmt.Errorf("unknown prompt %q in answers file %s, expected one of: %s", id, path, strings.Join(answerIDs, ", "))}
+		}
+
+		// checked before any command runs, rather than after it provisions
+		if allowed := ConfigEnums[answerEnums[id]]; allowed != nil && !slices.Contains(allowed, answer) {
+			return nil, UserError{Err: fmt.Errorf("invalid answer %q for %q in answers file %s, expected one of: %s", answer, id, path, strings.Join(allowed, ", "))}
+		}
+	}
+
+	if answers == nil {
+		answers = Answers{} // an empty file still answers nothing
+	}
+	return answers, nil
+}
+
+func ContextWithAnswers(ctx context.Context, answers Answers) context.Context {
+	return context.WithValue(ctx, answersKey, answers)
+}
+
+// AnswersFromContext returns the answers of ctx, or nil when no answers file
+// is used.
+func AnswersFromContext(ctx context.Context) Answers {
+	answers, _ := ctx.Value(answersKey).(Answers)
+	return answers
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadAnswers(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(t *testing.T, name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		path := write(t, "valid.toml", heredoc.Doc(`
+			org = "+new"
+			org-name = "Example Org"
+			service-name = "example"
+			cert-style = "acme"
+		`))
+
+		answers, err := LoadAnswers(path)
+		require.NoError(t, err)
+		require.Equal(t, Answers{
+			AnswerOrg:         AnswerNew,
+			AnswerOrgName:     "Example Org",
+			AnswerServiceName: "example",
+			AnswerCertStyle:   "acme",
+		}, answers)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		answers, err := LoadAnswers(write(t, "empty.toml", ""))
+		require.NoError(t, err)
+		require.NotNil(t, answers)
+	})
+
+	t.Run("unknown-prompt", func(t *testing.T) {
+		_, err := LoadAnswers(write(t, "unknown.toml", `colour = "blue"`))
+		require.ErrorAs(t, err, new(UserError))
+		require.ErrorContains(t, err, `unknown prompt "colour"`)
+	})
+
+	t.Run("invalid-enum", func(t *testing.T) {
+		_, err := LoadAnswers(write(t, "invalid-enum.toml", `cert-style = "acem"`))
+		require.ErrorAs(t, err, new(UserError))
+		require.ErrorContains(t, err, `invalid answer "acem" for "cert-style"`)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := LoadAnswers(filepath.Join(dir, "missing.toml"))
+		require.ErrorAs(t, err, new(UserError))
+	})
+}
@@ -1,40 +1,125 @@
 package api
 
 import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/base64"
-	"time"
+	"errors"
+	"fmt"
 
 	"github.com/anchordotdev/cli"
 	"golang.org/x/crypto/acme"
-	"golang.org/x/crypto/acme/autocert"
 )
 
-func ProvisionCert(eab *Eab, domains []string, acmeURL string) (*tls.Certificate, error) {
+// ProvisionCert orders a certificate for all of the domains, which may include
+// wildcards, from the ACME server at acmeURL. The first domain is the subject
+// common name, and every domain is a SAN of the certificate.
+func ProvisionCert(ctx context.Context, eab *Eab, domains []string, acmeURL string) (*tls.Certificate, error) {
+	if len(domains) == 0 {
+		return nil, errors.New("no domains to provision certificate for")
+	}
+
 	hmacKey, err := base64.URLEncoding.DecodeString(eab.HmacKey)
 	if err != nil {
 		return nil, err
 	}
 
-	mgr := &autocert.Manager{
-		Prompt:     autocert.AcceptTOS,
-		HostPolicy: autocert.HostWhitelist(domains...),
-		Client: &acme.Client{
-			DirectoryURL: acmeURL,
-			UserAgent:    cli.UserAgent(),
-		},
+	accountKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &acme.Client{
+		Key:          accountKey,
+		DirectoryURL: acmeURL,
+		UserAgent:    cli.UserAgent(),
+	}
+
+	acct := &acme.Account{
 		ExternalAccountBinding: &acme.ExternalAccountBinding{
 			KID: eab.Kid,
 			Key: hmacKey,
 		},
-		RenewBefore: 24 * time.Hour,
+	}
+	if _, err := client.Register(ctx, acct, acme.AcceptTOS); err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return nil, err
+	}
+
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(domains...))
+	if err != nil {
+		return nil, err
+	}
+
+	// lcl.host domains are authorized by the EAB of the service attachment, the
+	// challenge only needs accepting, like autocert does, without answering it
+	for _, authzURL := range order.AuthzURLs {
+		authz, err := client.GetAuthorization(ctx, authzURL)
+		if err != nil {
+			return nil, err
+		}
+		if authz.Status == acme.StatusValid {
+			continue
+		}
+		if authz.Status != acme.StatusPending {
+			return nil, fmt.Errorf("%s domain authorization is %s, is it attached to the service?", authz.Identifier.Value, authz.Status)
+		}
+
+		var chal *acme.Challenge
+		for _, c := range authz.Challenges {
+			if c.Type == "tls-alpn-01" || (chal == nil && c.Type == "http-01") {
+				chal = c
+			}
+		}
+		if chal == nil {
+			return nil, fmt.Errorf("%s domain has no supported ACME challenge", authz.Identifier.Value)
+		}
+		if _, err := client.Accept(ctx, chal); err != nil {
+			return nil, err
+		}
+		if _, err := client.WaitAuthorization(ctx, authz.URI); err != nil {
+			return nil, err
+		}
 	}
 
-	// TODO: switch to using ACME package here, so that extra domains can be sent through for SAN extension
-	clientHello := &tls.ClientHelloInfo{
-		ServerName:   domains[0],
-		CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256},
+	if order, err = client.WaitOrder(ctx, order.URI); err != nil {
+		return nil, err
+	}
+
+	certKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: domains[0]},
+		DNSNames: domains,
+	}, certKey)
+	if err != nil {
+		return nil, err
+	}
+
+	chainDER, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(chainDER) == 0 {
+		return nil, errors.New("no certificate in ACME order")
+	}
+
+	leaf, err := x509.ParseCertificate(chainDER[0])
+	if err != nil {
+		return nil, err
 	}
 
-	return mgr.GetCertificate(clientHello)
+	return &tls.Certificate{
+		Certificate: chainDER,
+		PrivateKey:  crypto.Signer(certKey),
+		Leaf:        leaf,
+	}, nil
 }
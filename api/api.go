@@ -13,8 +13,10 @@ import (
 	"net/url"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/eventlog"
 	"github.com/anchordotdev/cli/keyring"
 	"github.com/anchordotdev/cli/version"
 	"golang.org/x/exp/slices"
@@ -24,6 +26,7 @@ var (
 	ErrSignedOut            = errors.New("sign in required")
 	ErrTransient            = errors.New("transient error encountered, please retry")
 	ErrGnomeKeyringRequired = fmt.Errorf("gnome-keyring required for secure credential storage: %w", ErrSignedOut)
+	ErrInvalidPATToken      = errors.New("invalid PAT token")
 )
 
 type QueryParam func(url.Values)
@@ -52,6 +55,23 @@ type Session struct {
 
 // TODO: rename to NewSession
 func NewClient(ctx context.Context, cfg *cli.Config) (*Session, error) {
+	anc, err := NewClientUnchecked(ctx, cfg)
+	if err != nil {
+		return anc, err
+	}
+
+	if info, err := anc.UserInfo(ctx); err == nil {
+		if err := version.MinimumVersionCheck(info.MinimumCliVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	return anc, nil
+}
+
+// NewClientUnchecked is NewClient without the minimum CLI version check, for
+// commands that report the version status themselves.
+func NewClientUnchecked(ctx context.Context, cfg *cli.Config) (*Session, error) {
 	anc := &Session{
 		Client: &http.Client{
 			Transport: Middlewares{
@@ -64,6 +84,9 @@ func NewClient(ctx context.Context, cfg *cli.Config) (*Session, error) {
 					cfg: cfg,
 				},
 				autoRetrier,
+				requestLogger{
+					log: eventlog.FromContext(ctx),
+				},
 			}.RoundTripper(new(http.Transport)),
 		},
 		cfg: cfg,
@@ -85,7 +108,7 @@ func NewClient(ctx context.Context, cfg *cli.Config) (*Session, error) {
 		}
 
 		if apiToken != "" {
-			if !strings.HasPrefix(apiToken, "ap0_") || len(apiToken) != 64 {
+			if err := ValidatePATToken(apiToken); err != nil {
 				return nil, fmt.Errorf("read invalid PAT token from keyring")
 			}
 		}
@@ -96,15 +119,16 @@ func NewClient(ctx context.Context, cfg *cli.Config) (*Session, error) {
 		PAT:          apiToken,
 	}
 
-	if info, err := anc.UserInfo(ctx); err == nil {
-		if err := version.MinimumVersionCheck(info.MinimumCliVersion); err != nil {
-			return nil, err
-		}
-	}
-
 	return anc, nil
 }
 
+func ValidatePATToken(token string) error {
+	if !strings.HasPrefix(token, "ap0_") || len(token) != 64 {
+		return ErrInvalidPATToken
+	}
+	return nil
+}
+
 func attachServicePath(orgSlug, serviceSlug string) string {
 	return "/orgs/" + url.QueryEscape(orgSlug) + "/services/" + url.QueryEscape(serviceSlug) + "/actions/attach"
 }
@@ -471,6 +495,38 @@ var responseChecker = MiddlewareFunc(func(next http.RoundTripper) http.RoundTrip
 	})
 })
 
+// requestLogger records a summary of each request in the event log, without
+// headers or bodies.
+type requestLogger struct {
+	log *eventlog.Log
+}
+
+func (l requestLogger) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	if l.log == nil {
+		return next
+	}
+
+	return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		res, err := next.RoundTrip(req)
+
+		fields := eventlog.Fields{
+			"method":      req.Method,
+			"path":        req.URL.Path,
+			"duration_ms": time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = res.StatusCode
+			fields["request_id"] = res.Header.Get("X-Request-Id")
+		}
+		l.log.Event("api", fields)
+
+		return res, err
+	})
+}
+
 type urlRewriter struct {
 	url string
 }
@@ -564,6 +620,9 @@ func gnomeKeyringMissing(cfg *cli.Config) bool {
 	if cfg.GOOS() != "linux" {
 		return false
 	}
+	if backend := cfg.Keyring.Backend; backend != "" && backend != keyring.BackendSystem {
+		return false
+	}
 	if path, _ := exec.LookPath("gnome-keyring-daemon"); path != "" {
 		return false
 	}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/auth/models"
+	"github.com/anchordotdev/cli/keyring"
+	"github.com/anchordotdev/cli/ui"
+)
+
+var CmdAuthMigrate = cli.NewCmd[Migrate](CmdAuth, "migrate", func(cmd *cobra.Command) {
+	cfg := cli.ConfigFromCmd(cmd)
+
+	cmd.Flags().StringVar(&cfg.Auth.Migrate.To, "to", cli.Defaults.Auth.Migrate.To, "Keyring backend to move credentials to.")
+})
+
+type Migrate struct{}
+
+func (c Migrate) UI() cli.UI {
+	return cli.UI{
+		RunTUI: c.runTUI,
+	}
+}
+
+func (c *Migrate) runTUI(ctx context.Context, drv *ui.Driver) error {
+	cfg := cli.ConfigFromContext(ctx)
+
+	drv.Activate(ctx, models.MigrateHeader)
+
+	if cfg.Auth.Migrate.To == "" {
+		return cli.UserError{Err: errors.New("--to is required")}
+	}
+
+	dst, err := keyring.NewBackend(cfg, cfg.Auth.Migrate.To)
+	if err != nil {
+		return err
+	}
+
+	kr := keyring.Keyring{Config: cfg}
+	err = kr.Migrate(dst, keyring.APIToken, func() error {
+		// record the new backend, so later commands read the token from it
+		values := map[string]any{"keyring.backend": dst.Name()}
+		if dst.Name() == keyring.BackendFile && cfg.Keyring.File.Path != "" {
+			values["keyring.file.path"] = cfg.Keyring.File.Path
+		}
+		return cfg.SetUserTOML(values)
+	})
+
+	if errors.Is(err, keyring.ErrNotFound) {
+		drv.Activate(ctx, models.SignOutSignedOut)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if keyring.SameBackend(kr.Backend, dst) {
+		drv.Activate(ctx, &models.KeyringMigrated{
+			From:      kr.Backend.Name(),
+			To:        dst.Name(),
+			Unchanged: true,
+		})
+		return nil
+	}
+
+	drv.Activate(ctx, &models.KeyringMigrated{
+		From:       kr.Backend.Name(),
+		To:         dst.Name(),
+		ConfigPath: cfg.UserConfigPath(),
+	})
+
+	return nil
+}
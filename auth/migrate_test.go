@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/stretchr/testify/require"
+	gokeyring "github.com/zalando/go-keyring"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/clitest"
+	"github.com/anchordotdev/cli/cmdtest"
+	"github.com/anchordotdev/cli/keyring"
+	"github.com/anchordotdev/cli/ui/uitest"
+)
+
+func TestCmdAuthMigrate(t *testing.T) {
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdAuthMigrate, "auth", "migrate", "--help")
+	})
+
+	t.Run("--to file", func(t *testing.T) {
+		cfg := cmdtest.TestCfg(t, CmdAuthMigrate, "--to", "file")
+		require.Equal(t, "file", cfg.Auth.Migrate.To)
+	})
+}
+
+func TestMigrate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fs := clitest.TestFS{}
+
+	cfg := new(cli.Config)
+	cfg.API.URL = "http://test-auth-migrate.example.com/"
+	cfg.Keyring.File.Path = filepath.Join(t.TempDir(), "credentials")
+	cfg.Keyring.File.Passphrase = "test"
+	cfg.Auth.Migrate.To = keyring.BackendFile
+	cfg.Test.SystemFS = fs
+	cfg.Test.UserTOML = "home/anchor/config.toml"
+	ctx = cli.ContextWithConfig(ctx, cfg)
+
+	// mock the system keyring once, since MockMode resets it for every Keyring
+	gokeyring.MockInit()
+
+	kr := keyring.Keyring{Config: cfg}
+	require.NoError(t, kr.Set(keyring.APIToken, "ap0_test"))
+
+	drv, tm := uitest.TestTUI(ctx, t)
+
+	cmd := Migrate{}
+	require.NoError(t, cmd.UI().RunTUI(ctx, drv))
+	require.NoError(t, tm.Quit())
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+
+	require.Equal(t, "[keyring]\nbackend = 'file'\n\n[keyring.file]\npath = '"+cfg.Keyring.File.Path+"'\n", string(fs["home/anchor/config.toml"].Data))
+
+	// later commands read the token from the recorded backend
+	loaded := new(cli.Config)
+	loaded.Test.SystemFS = fs
+	loaded.Test.UserTOML = cfg.Test.UserTOML
+	require.NoError(t, loaded.Load(context.Background()))
+	require.Equal(t, keyring.BackendFile, loaded.Keyring.Backend)
+
+	loaded.API.URL = cfg.API.URL
+	loaded.Keyring.File.Passphrase = "test"
+
+	krFile := keyring.Keyring{Config: loaded}
+	token, err := krFile.Get(keyring.APIToken)
+	require.NoError(t, err)
+	require.Equal(t, "ap0_test", token)
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anchordotdev/cli/ui"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var MigrateHeader = ui.Section{
+	Name: "MigrateHeader",
+	Model: ui.MessageLines{
+		ui.Header(fmt.Sprintf("Move Credentials Between Keyring Backends %s", ui.Whisper("`anchor auth migrate`"))),
+	},
+}
+
+type KeyringMigrated struct {
+	From, To string
+
+	ConfigPath string // user config file recording the new backend
+	Unchanged  bool   // credentials already in the To backend
+}
+
+func (m *KeyringMigrated) Init() tea.Cmd { return nil }
+
+func (m *KeyringMigrated) Update(msg tea.Msg) (tea.Model, tea.Cmd) { return m, nil }
+
+func (m *KeyringMigrated) View() string {
+	var b strings.Builder
+
+	if m.Unchanged {
+		fmt.Fprintln(&b, ui.StepDone(fmt.Sprintf("Credentials already stored in %s keyring.", ui.Emphasize(m.To))))
+		return b.String()
+	}
+
+	fmt.Fprintln(&b, ui.StepDone(fmt.Sprintf("Moved credentials from %s to %s keyring.", ui.Emphasize(m.From), ui.Emphasize(m.To))))
+	fmt.Fprintln(&b, ui.StepHint(fmt.Sprintf("Saved keyring.backend = %q to %s for later commands.", m.To, m.ConfigPath)))
+
+	return b.String()
+}
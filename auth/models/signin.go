@@ -120,6 +120,7 @@ func (m *KeyringUnavailable) View() string {
 		fmt.Fprintln(&b, ui.StepHint("gnome-keyring is required for secure credential storage."))
 		fmt.Fprintln(&b, ui.StepHint("Please install with your host package manager"))
 	}
+	fmt.Fprintln(&b, ui.StepHint("Or set ANCHOR_KEYRING_BACKEND=file to use an encrypted credentials file."))
 
 	return b.String()
 }
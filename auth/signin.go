@@ -3,6 +3,9 @@ package auth
 import (
 	"context"
 	"errors"
+	"io"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/atotto/clipboard"
@@ -19,7 +22,11 @@ import (
 )
 
 var (
-	CmdAuthSignin = cli.NewCmd[SignIn](CmdAuth, "signin", func(cmd *cobra.Command) {})
+	CmdAuthSignin = cli.NewCmd[SignIn](CmdAuth, "signin", func(cmd *cobra.Command) {
+		cfg := cli.ConfigFromCmd(cmd)
+
+		cmd.Flags().BoolVar(&cfg.Auth.SignIn.WithToken, "with-token", cli.Defaults.Auth.SignIn.WithToken, "Read a Personal Access Token (PAT) from stdin.")
+	})
 
 	ErrSigninFailed = errors.New("sign in failed")
 )
@@ -28,6 +35,8 @@ type SignIn struct {
 	Source string
 
 	Hint tea.Model
+
+	TokenReader io.Reader // token source for --with-token, defaults to os.Stdin
 }
 
 func (s SignIn) UI() cli.UI {
@@ -41,6 +50,10 @@ func (s *SignIn) RunTUI(ctx context.Context, drv *ui.Driver) error {
 
 	drv.Activate(ctx, models.SignInHeader)
 
+	if cfg.Auth.SignIn.WithToken {
+		return s.withToken(ctx, cfg, drv)
+	}
+
 	if s.Hint == nil {
 		s.Hint = models.SignInHint
 	}
@@ -114,3 +127,50 @@ func (s *SignIn) RunTUI(ctx context.Context, drv *ui.Driver) error {
 
 	return nil
 }
+
+func (s *SignIn) withToken(ctx context.Context, cfg *cli.Config, drv *ui.Driver) error {
+	if s.TokenReader == nil {
+		s.TokenReader = os.Stdin
+	}
+
+	data, err := io.ReadAll(s.TokenReader)
+	if err != nil {
+		return err
+	}
+	patToken := strings.TrimSpace(string(data))
+
+	if err := api.ValidatePATToken(patToken); err != nil {
+		return cli.UserError{Err: errors.New("invalid PAT token read from stdin, expected an ap0_ prefixed token")}
+	}
+
+	drv.Activate(ctx, new(models.SignInChecker))
+
+	cfg.API.Token = patToken
+
+	anc, err := api.NewClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	userInfo, err := anc.UserInfo(ctx)
+	if errors.Is(err, api.ErrSignedOut) {
+		return cli.UserError{Err: ErrSigninFailed}
+	}
+	if err != nil {
+		return err
+	}
+
+	kr := keyring.Keyring{Config: cfg}
+	if err := kr.Set(keyring.APIToken, cfg.API.Token); err != nil {
+		return ui.Error{
+			Model: &models.KeyringUnavailable{
+				ShowGnomeKeyringHint: errors.Is(err, api.ErrGnomeKeyringRequired),
+			},
+			Err: err,
+		}
+	}
+
+	drv.Send(models.UserSignInMsg(userInfo.Whoami))
+
+	return nil
+}
@@ -1,15 +1,29 @@
 package auth
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+	gokeyring "github.com/zalando/go-keyring"
+
+	"github.com/anchordotdev/cli"
 	"github.com/anchordotdev/cli/cmdtest"
+	"github.com/anchordotdev/cli/keyring"
+	"github.com/anchordotdev/cli/ui/uitest"
 )
 
 func TestCmdAuthSignin(t *testing.T) {
 	t.Run("--help", func(t *testing.T) {
 		cmdtest.TestHelp(t, CmdAuthSignin, "auth", "signin", "--help")
 	})
+
+	t.Run("--with-token", func(t *testing.T) {
+		cfg := cmdtest.TestCfg(t, CmdAuthSignin, "--with-token")
+		require.True(t, cfg.Auth.SignIn.WithToken)
+	})
 }
 
 func TestSignIn(t *testing.T) {
@@ -24,4 +38,72 @@ func TestSignIn(t *testing.T) {
 	t.Run("invalid-config-token", func(t *testing.T) {
 		t.Skip("cli auth test not yet implemented")
 	})
+
+	t.Run("with-token-invalid", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		cfg := new(cli.Config)
+		cfg.API.URL = srv.URL
+		cfg.Auth.SignIn.WithToken = true
+		cfg.Keyring.MockMode = true
+		ctx = cli.ContextWithConfig(ctx, cfg)
+
+		cmd := SignIn{
+			TokenReader: strings.NewReader("not-a-pat-token\n"),
+		}
+
+		drv, tm := uitest.TestTUI(ctx, t)
+		defer func() { _ = tm.Quit() }()
+
+		err := cmd.RunTUI(ctx, drv)
+
+		var uerr cli.UserError
+		if !errors.As(err, &uerr) {
+			t.Fatalf("want user error for invalid token, got %v", err)
+		}
+	})
+
+	t.Run("with-token-valid", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		cfg := new(cli.Config)
+		cfg.API.URL = srv.URL
+		cfg.Auth.SignIn.WithToken = true
+		ctx = cli.ContextWithConfig(ctx, cfg)
+
+		// mock the system keyring once, since MockMode resets it for every
+		// Keyring, including the one reading the token back
+		gokeyring.MockInit()
+
+		// the mock API accepts any token, but sign in checks the PAT format
+		apiToken := "ap0_" + strings.Repeat("x", 60)
+		if srv.IsProxy() {
+			var err error
+			if apiToken, err = srv.GeneratePAT("anky@anchor.dev"); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		cmd := SignIn{
+			TokenReader: strings.NewReader(apiToken + "\n"),
+		}
+
+		drv, tm := uitest.TestTUI(ctx, t)
+		defer func() { _ = tm.Quit() }()
+
+		if err := cmd.RunTUI(ctx, drv); err != nil {
+			t.Fatal(err)
+		}
+
+		kr := keyring.Keyring{Config: cfg}
+		token, err := kr.Get(keyring.APIToken)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, got := apiToken, token; want != got {
+			t.Errorf("want stored token %q, got %q", want, got)
+		}
+	})
 }
@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/api"
+	"github.com/anchordotdev/cli/version"
+)
+
+const (
+	VersionStatusCurrent  = "current"
+	VersionStatusOutdated = "outdated"
+	VersionStatusUnknown  = "unknown"
+)
+
+var CmdAuthStatus = cli.NewCmd[Status](CmdAuth, "status", func(cmd *cobra.Command) {})
+
+type Status struct{}
+
+type StatusResult struct {
+	SignedIn       bool   `json:"signed_in"`
+	Whoami         string `json:"whoami,omitempty"`
+	PersonalOrg    string `json:"personal_org,omitempty"`
+	TokenSource    string `json:"token_source,omitempty"`
+	KeyringBackend string `json:"keyring_backend,omitempty"`
+	APIURL         string `json:"api_url"`
+
+	CLIVersion        string `json:"cli_version"`
+	MinimumCLIVersion string `json:"minimum_cli_version,omitempty"`
+	VersionStatus     string `json:"version_status"`
+}
+
+func (c Status) UI() cli.UI {
+	return cli.UI{
+		RunCLI: c.runCLI,
+	}
+}
+
+func (c *Status) runCLI(ctx context.Context, streams cli.Streams) error {
+	cfg := cli.ConfigFromContext(ctx)
+
+	cli.SkipReleaseCheck = true
+
+	res, err := c.status(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	cli.SetResult(ctx, res)
+
+	return res.writeText(streams.Out)
+}
+
+func (c *Status) status(ctx context.Context, cfg *cli.Config) (*StatusResult, error) {
+	res := &StatusResult{
+		APIURL:        cfg.API.URL,
+		CLIVersion:    cli.Version.Version,
+		VersionStatus: VersionStatusUnknown,
+	}
+
+	if _, source, err := activeToken(cfg); err == nil {
+		res.TokenSource = source
+		if source == "keyring" {
+			res.KeyringBackend = cfg.Keyring.Backend
+		}
+	}
+
+	// the version status is reported, rather than failing with NewClient
+	anc, err := api.NewClientUnchecked(ctx, cfg)
+	if errors.Is(err, api.ErrSignedOut) {
+		res.TokenSource = ""
+		res.KeyringBackend = ""
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := anc.UserInfo(ctx)
+	if errors.Is(err, api.ErrSignedOut) {
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res.SignedIn = true
+	res.Whoami = info.Whoami
+	res.PersonalOrg = info.PersonalOrg.Slug
+	res.MinimumCLIVersion = info.MinimumCliVersion
+
+	res.VersionStatus = VersionStatusCurrent
+	if err := version.MinimumVersionCheck(info.MinimumCliVersion); err != nil {
+		res.VersionStatus = VersionStatusOutdated
+	}
+
+	return res, nil
+}
+
+func (r *StatusResult) writeText(w io.Writer) error {
+	if !r.SignedIn {
+		_, err := fmt.Fprintf(w, "Not signed in.\nRun `anchor auth signin` to sign in.\n")
+		return err
+	}
+
+	source := r.TokenSource
+	if r.KeyringBackend != "" {
+		source = fmt.Sprintf("%s (%s)", r.TokenSource, r.KeyringBackend)
+	}
+
+	_, err := fmt.Fprintf(w, "Signed in as: %s\nPersonal org: %s\nToken source: %s\nAPI URL:      %s\nCLI version:  %s (%s)\n",
+		r.Whoami,
+		r.PersonalOrg,
+		source,
+		r.APIURL,
+		r.CLIVersion,
+		r.VersionStatus,
+	)
+	return err
+}
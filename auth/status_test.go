@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/cmdtest"
+)
+
+func TestCmdAuthStatus(t *testing.T) {
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdAuthStatus, "auth", "status", "--help")
+	})
+}
+
+func TestStatus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := new(cli.Config)
+	cfg.API.URL = srv.URL
+	cfg.Keyring.MockMode = true
+	ctx = cli.ContextWithConfig(ctx, cfg)
+
+	t.Run("signed-out", func(t *testing.T) {
+		var out bytes.Buffer
+
+		cmd := Status{}
+		require.NoError(t, cmd.UI().RunCLI(ctx, cli.Streams{Out: &out, Err: &out}))
+		require.Equal(t, "Not signed in.\nRun `anchor auth signin` to sign in.\n", out.String())
+
+		res, err := cmd.status(ctx, cfg)
+		require.NoError(t, err)
+		require.False(t, res.SignedIn)
+		require.Equal(t, srv.URL, res.APIURL)
+	})
+
+	t.Run("signed-in", func(t *testing.T) {
+		apiToken, err := srv.GeneratePAT("anky@anchor.dev")
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.API.Token = apiToken
+		cfg.Via.Flags = new(cli.Config)
+		cfg.Via.Flags.API.Token = apiToken
+
+		var out bytes.Buffer
+
+		cmd := Status{}
+		require.NoError(t, cmd.UI().RunCLI(ctx, cli.Streams{Out: &out, Err: &out}))
+		require.Contains(t, out.String(), "Signed in as: anky@anchor.dev\n")
+
+		res, err := cmd.status(ctx, cfg)
+		require.NoError(t, err)
+		require.True(t, res.SignedIn)
+		require.Equal(t, "anky@anchor.dev", res.Whoami)
+		require.Equal(t, "flag", res.TokenSource)
+	})
+}
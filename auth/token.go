@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/keyring"
+)
+
+var ErrTokenNotConfirmed = errors.New("token not printed, confirmation declined")
+
+var CmdAuthToken = cli.NewCmd[Token](CmdAuth, "token", func(cmd *cobra.Command) {})
+
+type Token struct{}
+
+func (c Token) UI() cli.UI {
+	return cli.UI{
+		RunCLI: c.runCLI,
+	}
+}
+
+func (c *Token) runCLI(ctx context.Context, streams cli.Streams) error {
+	cfg := cli.ConfigFromContext(ctx)
+
+	cli.SkipReleaseCheck = true
+
+	token, _, err := activeToken(cfg)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return cli.UserError{Err: errors.New("not signed in, run `anchor auth signin` to sign in")}
+	}
+	if err != nil {
+		return err
+	}
+
+	if cli.IsTerminal(streams.Out) && !cfg.NonInteractive {
+		fmt.Fprint(streams.Err, "This will print your Personal Access Token to the terminal. Continue? [y/N] ")
+
+		answer, _ := bufio.NewReader(streams.In).ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+		default:
+			return cli.UserError{Err: ErrTokenNotConfirmed}
+		}
+	}
+
+	_, err = fmt.Fprintln(streams.Out, token)
+	return err
+}
+
+// activeToken returns the API token in use and where it was set: flag, env,
+// keyring or config, for a token set in a config file.
+func activeToken(cfg *cli.Config) (token, source string, err error) {
+	if cfg.API.Token != "" {
+		return cfg.API.Token, apiTokenSource(cfg), nil
+	}
+
+	kr := keyring.Keyring{Config: cfg}
+	token, err = kr.Get(keyring.APIToken)
+	return token, "keyring", err
+}
+
+func apiTokenSource(cfg *cli.Config) string {
+	if cfg.Via.Flags != nil && cfg.Via.Flags.API.Token != "" {
+		return "flag"
+	}
+	if cfg.Via.ENV != nil && cfg.Via.ENV.API.Token != "" {
+		return "env"
+	}
+	return "config" // set in a config file
+}
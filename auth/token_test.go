@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/cmdtest"
+	"github.com/anchordotdev/cli/keyring"
+)
+
+func TestCmdAuthToken(t *testing.T) {
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdAuthToken, "auth", "token", "--help")
+	})
+}
+
+func TestToken(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := new(cli.Config)
+	cfg.Keyring.MockMode = true
+	ctx = cli.ContextWithConfig(ctx, cfg)
+
+	t.Run("signed-out", func(t *testing.T) {
+		var out bytes.Buffer
+
+		cmd := Token{}
+		err := cmd.UI().RunCLI(ctx, cli.Streams{In: strings.NewReader(""), Out: &out, Err: &out})
+
+		var uerr cli.UserError
+		require.True(t, errors.As(err, &uerr))
+		require.Empty(t, out.String())
+	})
+
+	t.Run("config-token", func(t *testing.T) {
+		cfg.API.Token = "ap0_f00f00f"
+		defer func() { cfg.API.Token = "" }()
+
+		var out bytes.Buffer
+
+		cmd := Token{}
+		err := cmd.UI().RunCLI(ctx, cli.Streams{In: strings.NewReader(""), Out: &out, Err: &out})
+
+		require.NoError(t, err)
+		require.Equal(t, "ap0_f00f00f\n", out.String())
+	})
+}
+
+func TestActiveTokenSource(t *testing.T) {
+	flags := new(cli.Config)
+	flags.API.Token = "ap0_flag"
+
+	env := new(cli.Config)
+	env.API.Token = "ap0_env"
+
+	tests := []struct {
+		name string
+
+		token      string
+		flags, env *cli.Config
+
+		want string
+	}{
+		{name: "flag", token: "ap0_flag", flags: flags, want: "flag"},
+		{name: "env", token: "ap0_env", env: env, want: "env"},
+		{name: "flag-over-env", token: "ap0_flag", flags: flags, env: env, want: "flag"},
+		{name: "config", token: "ap0_config", want: "config"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := new(cli.Config)
+			cfg.API.Token = test.token
+			cfg.Via.Flags = test.flags
+			cfg.Via.ENV = test.env
+
+			token, source, err := activeToken(cfg)
+			require.NoError(t, err)
+			require.Equal(t, test.token, token)
+			require.Equal(t, test.want, source)
+		})
+	}
+
+	t.Run("keyring", func(t *testing.T) {
+		cfg := new(cli.Config)
+		cfg.Keyring.MockMode = true
+
+		_, source, err := activeToken(cfg)
+		require.ErrorIs(t, err, keyring.ErrNotFound)
+		require.Equal(t, "keyring", source)
+	})
+}
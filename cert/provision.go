@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/anchordotdev/cli"
 	"github.com/anchordotdev/cli/cert/models"
@@ -43,14 +44,7 @@ func (p *Provision) Perform(ctx context.Context, drv *ui.Driver) error {
 
 	cert := p.Cert
 
-	prefix := cert.Leaf.Subject.CommonName
-	if num := len(p.Domains); num > 1 {
-		prefix += "+" + strconv.Itoa(num-1)
-	}
-
-	certFile := fmt.Sprintf("./%s-cert.pem", prefix)
-	chainFile := fmt.Sprintf("./%s-chain.pem", prefix)
-	keyFile := fmt.Sprintf("./%s-key.pem", prefix)
+	certFile, chainFile, keyFile := Filenames(cert.Leaf.Subject.CommonName, p.Domains)
 
 	certBlock := &pem.Block{
 		Type:  "CERTIFICATE",
@@ -63,15 +57,7 @@ func (p *Provision) Perform(ctx context.Context, drv *ui.Driver) error {
 		}
 	}
 
-	var chainData []byte
-	for _, certDER := range cert.Certificate {
-		chainBlock := &pem.Block{
-			Type:  "CERTIFICATE",
-			Bytes: certDER,
-		}
-
-		chainData = append(chainData, pem.EncodeToMemory(chainBlock)...)
-	}
+	chainData := ChainPEM(cert)
 
 	if !cfg.Trust.MockMode {
 		if err := os.WriteFile(chainFile, chainData, 0644); err != nil {
@@ -79,19 +65,13 @@ func (p *Provision) Perform(ctx context.Context, drv *ui.Driver) error {
 		}
 	}
 
-	keyDER, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	keyData, err := KeyPEM(cert)
 	if err != nil {
 		return err
 	}
 
-	keyBlock := &pem.Block{
-		Type:    "PRIVATE KEY",
-		Headers: make(map[string]string),
-		Bytes:   keyDER,
-	}
-
 	if !cfg.Trust.MockMode {
-		if err := os.WriteFile(keyFile, pem.EncodeToMemory(keyBlock), 0644); err != nil {
+		if err := os.WriteFile(keyFile, keyData, 0644); err != nil {
 			return err
 		}
 	}
@@ -99,3 +79,47 @@ func (p *Provision) Perform(ctx context.Context, drv *ui.Driver) error {
 	drv.Send(models.ProvisionedFiles{certFile, chainFile, keyFile})
 	return nil
 }
+
+// Filenames returns the paths of the certificate, chain and key files
+// provisioned for a certificate with commonName and domains.
+func Filenames(commonName string, domains []string) (certFile, chainFile, keyFile string) {
+	// like mkcert, since * is awkward in file names
+	prefix := strings.Replace(commonName, "*", "_wildcard", 1)
+	if num := len(domains); num > 1 {
+		prefix += "+" + strconv.Itoa(num-1)
+	}
+
+	certFile = fmt.Sprintf("./%s-cert.pem", prefix)
+	chainFile = fmt.Sprintf("./%s-chain.pem", prefix)
+	keyFile = fmt.Sprintf("./%s-key.pem", prefix)
+	return
+}
+
+// ChainPEM returns the PEM encoded certificate chain of cert, leaf first.
+func ChainPEM(cert *tls.Certificate) []byte {
+	var data []byte
+	for _, certDER := range cert.Certificate {
+		chainBlock := &pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: certDER,
+		}
+
+		data = append(data, pem.EncodeToMemory(chainBlock)...)
+	}
+	return data
+}
+
+// KeyPEM returns the PEM encoded PKCS #8 private key of cert.
+func KeyPEM(cert *tls.Certificate) ([]byte, error) {
+	keyDER, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	keyBlock := &pem.Block{
+		Type:    "PRIVATE KEY",
+		Headers: make(map[string]string),
+		Bytes:   keyDER,
+	}
+	return pem.EncodeToMemory(keyBlock), nil
+}
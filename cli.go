@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"os/exec"
@@ -110,6 +111,29 @@ func defaultConfig() *Config {
 
 type UI struct {
 	RunTUI func(context.Context, *ui.Driver) error
+
+	// RunCLI is used instead of RunTUI for commands that write plain output,
+	// such as for scripts, without starting the TUI.
+	RunCLI func(context.Context, Streams) error
+}
+
+type Streams struct {
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+}
+
+// IsTerminal reports whether w is a terminal.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
 }
 
 type contextKey int
@@ -117,8 +141,18 @@ type contextKey int
 const (
 	configKey contextKey = iota
 	calledAsKey
+	argsKey
+	resultKey
+	answersKey
 )
 
+func ArgsFromContext(ctx context.Context) []string {
+	if args, ok := ctx.Value(argsKey).([]string); ok {
+		return args
+	}
+	return nil
+}
+
 func CalledAsFromContext(ctx context.Context) string {
 	if calledAs, ok := ctx.Value(calledAsKey).(string); ok {
 		return calledAs
@@ -137,6 +171,10 @@ func ConfigFromCmd(cmd *cobra.Command) *Config {
 	return ConfigFromContext(cmd.Context())
 }
 
+func ContextWithArgs(ctx context.Context, args []string) context.Context {
+	return context.WithValue(ctx, argsKey, args)
+}
+
 func ContextWithCalledAs(ctx context.Context, calledAs string) context.Context {
 	return context.WithValue(ctx, calledAsKey, calledAs)
 }
@@ -214,14 +252,65 @@ type UserError struct {
 
 func (u UserError) Error() string { return u.Err.Error() }
 
+// Exit statuses of commands, unless set by an ExitError. The credential helper
+// statuses are only returned by credential-helper commands, and have their own
+// range so the programs calling the helper can tell them from the others.
+const (
+	ExitCodeOK        = 0
+	ExitCodeError     = 1   // unexpected errors
+	ExitCodeUserError = 2   // invalid usage, config or input, or missing input
+	ExitCodeCanceled  = 130 // interrupted
+
+	ExitCodeCredentialHelperInvalidRequest = 10 // malformed or incomplete request
+	ExitCodeCredentialHelperSignedOut      = 11 // not signed in
+	ExitCodeCredentialHelperNotFound       = 12 // org, realm, service or attachment not found
+)
+
+// ExitError sets the process exit status for Err, which otherwise depends on
+// the kind of error.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e ExitError) Error() string { return e.Err.Error() }
+func (e ExitError) Unwrap() error { return e.Err }
+
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+
+	var eerr ExitError
+	if errors.As(err, &eerr) {
+		return eerr.Code
+	}
+
+	var uerr UserError
+	if errors.As(err, &uerr) {
+		return ExitCodeUserError
+	}
+	if errors.As(err, new(MissingInputError)) {
+		return ExitCodeUserError
+	}
+	if errors.Is(err, context.Canceled) {
+		return ExitCodeCanceled
+	}
+	return ExitCodeError
+}
+
 func isReportable(err error) bool {
 	switch err.(type) {
-	case UserError:
+	case UserError, ExitError:
 		return false
 	case ui.Error:
 		return false
 	}
 
+	if errors.As(err, new(MissingInputError)) {
+		return false
+	}
+
 	var terr truststore.Error
 	if errors.As(err, &terr) {
 		return false
@@ -168,3 +168,32 @@ func (m *TestHint) View() string {
 }
 
 var Timestamp, _ = time.Parse(time.RFC3339Nano, "2024-01-02T15:04:05.987654321Z")
+
+func TestExitCode(t *testing.T) {
+	if want, got := 0, cli.ExitCode(nil); want != got {
+		t.Errorf("want exit code %d for nil error, got %d", want, got)
+	}
+	if want, got := 1, cli.ExitCode(testErr); want != got {
+		t.Errorf("want exit code %d for plain error, got %d", want, got)
+	}
+
+	err := fmt.Errorf("wrapped: %w", cli.ExitError{Code: 3, Err: testErr})
+	if want, got := 3, cli.ExitCode(err); want != got {
+		t.Errorf("want exit code %d for wrapped exit error, got %d", want, got)
+	}
+	if !errors.Is(err, testErr) {
+		t.Errorf("want exit error to unwrap to %v", testErr)
+	}
+
+	err = fmt.Errorf("wrapped: %w", cli.UserError{Err: testErr})
+	if want, got := cli.ExitCodeUserError, cli.ExitCode(err); want != got {
+		t.Errorf("want exit code %d for user error, got %d", want, got)
+	}
+	err = fmt.Errorf("wrapped: %w", cli.MissingInputError{})
+	if want, got := cli.ExitCodeUserError, cli.ExitCode(err); want != got {
+		t.Errorf("want exit code %d for missing input error, got %d", want, got)
+	}
+	if want, got := cli.ExitCodeCanceled, cli.ExitCode(context.Canceled); want != got {
+		t.Errorf("want exit code %d for canceled context, got %d", want, got)
+	}
+}
@@ -3,10 +3,15 @@ package cli
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/anchordotdev/cli/eventlog"
 	"github.com/anchordotdev/cli/stacktrace"
 	"github.com/anchordotdev/cli/ui"
+	"github.com/charmbracelet/x/term"
 	"github.com/spf13/cobra"
 )
 
@@ -32,6 +37,28 @@ var rootDef = CmdDef{
 		anchor is a command line interface for the Anchor certificate management platform.
 
 		It provides a developer friendly interface for certificate management.
+
+		For scripts and CI, use --output json or --output text (or ANCHOR_OUTPUT) to
+		skip the interactive UI. In json mode, each step is written as a line of JSON,
+		followed by a final result with the exit code. Exit codes are 0 on success, 1
+		for unexpected errors, 2 for invalid usage, config or input and 130 when
+		interrupted.
+
+		With NON_INTERACTIVE set, or an --output mode, commands never wait for input.
+		Confirmations are skipped, prompts with a default use it and other prompts fail
+		with exit code 2, listing the flags or environment variables that answer them.
+		Use --answers (or ANCHOR_ANSWERS) to answer prompts from a TOML file instead,
+		as described in anchor lcl setup --help.
+
+		To debug a run, use --log-file (or ANCHOR_LOG) to append a JSON log of its
+		steps, API requests and trust store changes to a file, with secrets redacted.
+		The same log of the last command, other than debug and credential-helper, is
+		kept as last-command.log in the user cache directory for anchor debug bundle.
+		Use --record to record what the interactive UI shows as an asciicast, played
+		back by anchor debug replay.
+
+		Other commands run plugins, executables named anchor-<command>. See anchor
+		plugin --help for details.
 	`),
 
 	SubDefs: []CmdDef{
@@ -43,6 +70,22 @@ var rootDef = CmdDef{
 			Short: "Manage Anchor.dev Authentication",
 
 			SubDefs: []CmdDef{
+				{
+					Name: "migrate",
+
+					Use:   "migrate --to <backend> [flags]",
+					Args:  cobra.NoArgs,
+					Short: "Move Credentials Between Keyring Backends",
+					Long: heredoc.Doc(`
+						Move your Personal Access Token (PAT) from the configured keyring backend to
+						another backend, then remove it from the original.
+
+						Available backends are system, file, pass, gopass and helper. The new backend is
+						saved as keyring.backend in the user config file for later commands, and can be
+						overridden with ANCHOR_KEYRING_BACKEND. A project anchor.toml cannot select the
+						keyring backend.
+					`),
+				},
 				{
 					Name: "signin",
 
@@ -54,6 +97,9 @@ var rootDef = CmdDef{
 
 						Generate a new Personal Access Token (PAT) and store it in the system keychain
 						for the local system user.
+
+						With --with-token, read an existing PAT from stdin instead, without opening a
+						browser or prompting. For example: anchor auth signin --with-token < token.txt
 					`),
 				},
 				{
@@ -69,6 +115,34 @@ var rootDef = CmdDef{
 						system user.
 					`),
 				},
+				{
+					Name: "status",
+
+					Use:   "status [flags]",
+					Args:  cobra.NoArgs,
+					Short: "Report Authentication Status",
+					Long: heredoc.Doc(`
+						Report the sign-in state of your local system user, including the account,
+						personal organization, where the Personal Access Token (PAT) was read from
+						(flag, env or keyring), the API URL and whether this CLI version is still
+						supported.
+
+						Use --output json for machine-readable output.
+					`),
+				},
+				{
+					Name: "token",
+
+					Use:   "token [flags]",
+					Args:  cobra.NoArgs,
+					Short: "Print the Active Personal Access Token",
+					Long: heredoc.Doc(`
+						Print the Personal Access Token (PAT) in use for your local system user, for
+						use in scripts. For example: export API_TOKEN=$(anchor auth token)
+
+						When output is a terminal, confirm before printing the token.
+					`),
+				},
 				{
 					Name: "whoami",
 
@@ -81,6 +155,183 @@ var rootDef = CmdDef{
 				},
 			},
 		},
+		{
+			Name: "config",
+
+			Use:   "config [flags]",
+			Args:  cobra.NoArgs,
+			Short: "Manage CLI Configuration",
+			Long: heredoc.Doc(`
+				Manage CLI configuration.
+
+				Configuration is merged from the following sources, later sources taking
+				precedence over earlier ones:
+
+				  - built-in defaults
+				  - the system config file (/etc/anchor/config.toml)
+				  - the user config file ($XDG_CONFIG_HOME/anchor/config.toml)
+				  - the project anchor.toml, found in the current directory or a parent
+				    directory up to the root of the git repository
+				  - environment variables
+				  - command line flags
+
+				Use --config or ANCHOR_CONFIG to choose the project config file explicitly,
+				and --skip-config or ANCHOR_SKIP_CONFIG to skip loading config files.
+			`),
+			SubDefs: []CmdDef{
+				{
+					Name: "get",
+
+					Use:   "get <key> [flags]",
+					Args:  cobra.ExactArgs(1),
+					Short: "Print a Configuration Value",
+					Long: heredoc.Doc(`
+						Print the effective value of a configuration key, addressed by its TOML path,
+						for example: anchor config get service.cert-style
+
+						Lists are printed comma separated and secrets are redacted.
+					`),
+				},
+				{
+					Name: "migrate",
+
+					Use:   "migrate [flags]",
+					Args:  cobra.NoArgs,
+					Short: "Upgrade anchor.toml to the Current Version",
+					Long: heredoc.Doc(`
+						Rewrite anchor.toml in the layout of this CLI version, renaming or moving keys
+						from older versions and recording the new version. Comments and formatting are
+						preserved.
+
+						Older files are also upgraded in memory whenever they are loaded, while files
+						written by a newer version of the CLI fail to load.
+					`),
+				},
+				{
+					Name: "schema",
+
+					Use:   "schema [flags]",
+					Args:  cobra.NoArgs,
+					Short: "Print a JSON Schema for anchor.toml",
+					Long: heredoc.Doc(`
+						Print a JSON Schema describing the keys and values of anchor.toml, generated
+						from this version of the CLI. Read-only keys are not included.
+
+						Point your editor at the schema for completion and validation, for example by
+						adding #:schema ./anchor.schema.json at the top of anchor.toml.
+					`),
+				},
+				{
+					Name: "set",
+
+					Use:   "set <key> <value> [flags]",
+					Args:  cobra.ExactArgs(2),
+					Short: "Store a Configuration Value in anchor.toml",
+					Long: heredoc.Doc(`
+						Store a value for a configuration key, addressed by its TOML path, in
+						anchor.toml. For example: anchor config set lcl-host.realm-apid localhost
+
+						The value must match the type of the key, with lists given comma separated.
+						Read-only keys cannot be stored. Comments and formatting in anchor.toml are
+						preserved.
+					`),
+				},
+				{
+					Name: "show",
+
+					Use:   "show [flags]",
+					Args:  cobra.NoArgs,
+					Short: "Show Effective Configuration",
+					Long: heredoc.Doc(`
+						Print the effective configuration, combined from defaults, config files and
+						environment variables, along with the source of every value. Secrets such as
+						the API token are redacted.
+
+						With --validate, instead check anchor.toml for unknown keys and invalid values.
+					`),
+				},
+				{
+					Name: "unset",
+
+					Use:   "unset <key> [flags]",
+					Args:  cobra.ExactArgs(1),
+					Short: "Remove a Configuration Value from anchor.toml",
+					Long: heredoc.Doc(`
+						Remove the value for a configuration key, addressed by its TOML path, from
+						anchor.toml so that the default applies again.
+					`),
+				},
+			},
+		},
+		{
+			Name: "credential-helper",
+
+			Use:   "credential-helper [flags]",
+			Args:  cobra.NoArgs,
+			Short: "Provide ACME Credentials to Other Tools",
+			SubDefs: []CmdDef{
+				{
+					Name: "get",
+
+					Use:   "get [flags]",
+					Args:  cobra.NoArgs,
+					Short: "Write ACME Credentials for a Service as JSON",
+					Long: heredoc.Doc(`
+						Read a JSON request from stdin and write the ACME credentials for the service
+						to stdout as JSON, without any interactive output.
+
+						The request has the form {"org": "...", "realm": "...", "service": "..."} with
+						an optional "chain" (default "ca"). Missing values fall back to anchor.toml.
+						The response has the ACME_DIRECTORY_URL, ACME_KID, ACME_HMAC_KEY and
+						ACME_CONTACT keys.
+
+						Exit status is 0 on success, 1 on unexpected errors, 10 for an invalid
+						request, 11 when not signed in and 12 when the org, realm, service or
+						attachment is not found.
+					`),
+				},
+			},
+		},
+		{
+			Name: "debug",
+
+			Use:   "debug [flags]",
+			Args:  cobra.NoArgs,
+			Short: "Collect Diagnostics for Reporting Problems",
+			SubDefs: []CmdDef{
+				{
+					Name: "bundle",
+
+					Use:   "bundle [flags]",
+					Args:  cobra.NoArgs,
+					Short: "Write a Diagnostic Bundle for Offline Reporting",
+					Long: heredoc.Doc(`
+						Write a gzipped tarball of diagnostics to attach to an issue, for machines
+						that cannot open an issue in the browser.
+
+						The bundle contains the CLI version, the config with the source of each
+						value, the event log and any stack trace of the last command, the keyring
+						backend status, the subject and fingerprint of each CA certificate in the
+						trust stores, and the DNS resolution of lcl.host.
+
+						Secrets, personal access tokens and the home directory are redacted. Runs
+						of anchor debug commands are not recorded as the last command.
+					`),
+				},
+				{
+					Name: "replay",
+
+					Use:   "replay <file> [flags]",
+					Args:  cobra.ExactArgs(1),
+					Short: "Play Back a Recorded Session",
+					Long: heredoc.Doc(`
+						Print an asciicast recording, as written by --record, to the terminal at
+						its original speed. Recordings are asciinema v2 files, which can also be
+						played with asciinema.
+					`),
+				},
+			},
+		},
 		{
 			Name: "lcl",
 
@@ -124,6 +375,34 @@ var rootDef = CmdDef{
 					Use:   "mkcert [flags]",
 					Args:  cobra.NoArgs,
 					Short: "Provision Certificate for lcl.host Local Development",
+					Long: heredoc.Doc(`
+						Provision a certificate for lcl.host local development, with every one of
+						--domains as a name of the certificate. A domain may be a wildcard, like
+						*.app.lcl.host, to cover all of its subdomains. Domains missing from the
+						service are attached to it first.
+					`),
+				},
+				{
+					Name: "proxy",
+
+					Use:   "proxy [flags]",
+					Args:  cobra.NoArgs,
+					Short: "Proxy lcl.host HTTPS to a Local Application",
+					Long: heredoc.Doc(`
+						Serve a local application over lcl.host HTTPS, for applications that are
+						hard to configure for TLS, like legacy development servers, Vite and
+						Storybook.
+
+						The proxy terminates TLS with an lcl.host certificate provisioned for
+						--domain, which renews automatically while it runs, and forwards HTTP/1.1,
+						HTTP/2 and WebSocket requests to --to with X-Forwarded-For, -Host and
+						-Proto headers. Plain HTTP requests on the same address are forwarded
+						too, or redirected to HTTPS with --redirect-http.
+
+						For example:
+
+						  anchor lcl proxy --to http://localhost:3000 --domain app.lcl.host
+					`),
 				},
 				{
 					Name: "setup",
@@ -131,6 +410,40 @@ var rootDef = CmdDef{
 					Use:   "setup [flags]",
 					Args:  cobra.NoArgs,
 					Short: "Setup lcl.host Application",
+					Long: heredoc.Doc(`
+						Setup lcl.host HTTPS for an application in your local development environment.
+
+						With ACME certificates, setup offers to write the integration for Go,
+						Node.js, Next.js, Rails, Django and Flask applications, or the TLS config
+						for nginx, Apache and Caddy, into your project, previewing the changes as
+						a diff first. Otherwise, or when skipped, it opens your setup guide.
+
+						For PostgreSQL, MySQL and MongoDB services, setup writes the certificate,
+						key and realm CA bundle with the permissions the server requires, and
+						shows the TLS config lines for the server and a verifying connection
+						string for clients.
+
+						The certificate is for <domain>.lcl.host and <domain>.localhost, or for the
+						--domains given instead, which may include wildcards like *.app.lcl.host.
+
+						To script setup, give --answers a TOML file answering its prompts by
+						identifier: org, org-name, realm, service, service-name, category, domain,
+						cert-style, integration and env-output. Use "+new" for org or service to
+						create a new one, and "write" or "skip" for integration. Flags and
+						environment variables take precedence over answers, and any other prompt
+						is an error.
+
+						For example:
+
+						  org = "+new"
+						  org-name = "Example"
+						  service = "+new"
+						  service-name = "example"
+						  category = "go"
+						  domain = "example"
+						  cert-style = "acme"
+						  integration = "write"
+					`),
 				},
 				{
 					Name: "trust",
@@ -139,6 +452,21 @@ var rootDef = CmdDef{
 					Args:  cobra.NoArgs,
 					Short: "Install CA Certificates for lcl.host Local Development",
 				},
+				{
+					Name: "up",
+
+					Use:   "up [flags]",
+					Args:  cobra.NoArgs,
+					Short: "Provision and Verify Every Service in the Workspace",
+					Long: heredoc.Doc(`
+						Provision lcl.host certificates for every [[services]] entry in anchor.toml
+						concurrently, then verify their domains and certificates.
+
+						Services are created in the workspace [org] and [lcl-host] realm when needed,
+						with certificates and keys written to the cert-file and key-file of each
+						service. Use --service to bring up a single service of the workspace.
+					`),
+				},
 			},
 		},
 		{
@@ -157,6 +485,43 @@ var rootDef = CmdDef{
 				},
 			},
 		},
+		{
+			Name: "plugin",
+
+			Use:   "plugin [flags]",
+			Args:  cobra.NoArgs,
+			Short: "Manage Plugins",
+			Long: heredoc.Doc(`
+				Plugins add subcommands to anchor without changing it. A plugin is an
+				executable named anchor-<name>, run as anchor <name> with the remaining
+				arguments.
+
+				Plugins are found in the plugins directory next to the user config file
+				($XDG_CONFIG_HOME/anchor/plugins), then on PATH. Built-in commands take
+				precedence over plugins of the same name.
+
+				The resolved configuration is passed to plugins in the environment as
+				API_URL, API_TOKEN, ORG, REALM and SERVICE, the same variables anchor reads,
+				so plugins can call back into anchor or the Anchor API. API_TOKEN is only
+				passed to plugins in the plugins directory, unless plugin.path-token is set
+				in the user config file (or ANCHOR_PLUGIN_PATH_TOKEN) to pass it to plugins
+				on PATH too.
+			`),
+			SubDefs: []CmdDef{
+				{
+					Name: "list",
+
+					Use:   "list [flags]",
+					Args:  cobra.NoArgs,
+					Short: "List Installed Plugins",
+					Long: heredoc.Doc(`
+						List the plugins found in the plugins directory and on PATH, along with
+						their paths. Plugins hidden by a built-in command or by an earlier plugin
+						of the same name are marked as shadowed.
+					`),
+				},
+			},
+		},
 		{
 			Name: "service",
 
@@ -164,6 +529,23 @@ var rootDef = CmdDef{
 			Args:  cobra.NoArgs,
 			Short: "Manage services",
 			SubDefs: []CmdDef{
+				{
+					Name: "config",
+
+					Use:   "config [flags]",
+					Args:  cobra.NoArgs,
+					Short: "Generate Web Server Config for Service",
+					Long: heredoc.Doc(`
+						Generate the TLS config for serving a service with nginx, Apache or Caddy.
+						The web server defaults to the category of the service, use --server to
+						choose another.
+
+						The nginx and Apache configs use the certificate files of
+						"anchor lcl mkcert". The Caddy config provisions and renews certificates
+						itself, from the ACME directory of the realm with the ACME_KID and
+						ACME_HMAC_KEY of "anchor service env".
+					`),
+				},
 				{
 					Name: "env",
 
@@ -193,6 +575,11 @@ var rootDef = CmdDef{
 
 				After installation of the AnchorCA certificates, Leaf certificates under the
 				AnchorCA certificates will be trusted by browsers and programs on your system.
+
+				Each update is journaled as it is applied. When an update fails or is
+				interrupted, the certificates it installed are removed again. If that is not
+				possible, finish it with "anchor trust resume" or undo it with
+				"anchor trust rollback".
 			`),
 			SubDefs: []CmdDef{
 				{
@@ -221,6 +608,30 @@ var rootDef = CmdDef{
 					Args:  cobra.NoArgs,
 					Short: "Clean CA Certificates from your Local Trust Store(s)",
 				},
+				{
+					Name: "resume",
+
+					Use:   "resume [flags]",
+					Args:  cobra.NoArgs,
+					Short: "Finish an Interrupted Update of your Local Trust Store(s)",
+					Long: heredoc.Doc(`
+						Install the CA certificates of a trust store update that was interrupted,
+						such as by a crash or a lost sudo session, as recorded in its journal.
+						Certificates already installed are skipped.
+					`),
+				},
+				{
+					Name: "rollback",
+
+					Use:   "rollback [flags]",
+					Args:  cobra.NoArgs,
+					Short: "Undo an Interrupted Update of your Local Trust Store(s)",
+					Long: heredoc.Doc(`
+						Remove the CA certificates installed by a trust store update that was
+						interrupted, as recorded in its journal, leaving the trust stores as they
+						were before the update.
+					`),
+				},
 			},
 		},
 		{
@@ -290,6 +701,15 @@ func NewCmd[T UIer](parent *cobra.Command, name string, fn func(*cobra.Command))
 		cmd.RunE = func(cmd *cobra.Command, args []string) (returnedError error) {
 			ctx := cmd.Context()
 
+			// persistent flags are bound to the config of the parent defining them
+			for parent := cmd.Parent(); parent != nil; parent = parent.Parent() {
+				if parentCfg := ConfigFromCmd(parent); parentCfg != nil {
+					if err := ConfigFromCmd(cmd).setNonDefaults(parentCfg); err != nil {
+						return err
+					}
+				}
+			}
+
 			cfg := new(Config)
 			if err := cfg.Load(ctx); err != nil {
 				return err
@@ -314,8 +734,93 @@ func NewCmd[T UIer](parent *cobra.Command, name string, fn func(*cobra.Command))
 			defer cancel(nil)
 
 			ctx = ContextWithCalledAs(ctx, cmd.CalledAs())
+			ctx = ContextWithArgs(ctx, args)
+
+			if err := checkOutput(cfg); err != nil {
+				return err
+			}
+			if cfg.Record != "" && (t.UI().RunCLI != nil || cfg.Output != "") {
+				return UserError{Err: fmt.Errorf("--record requires the interactive UI, which %s does not use", cmd.CommandPath())}
+			}
+
+			if cfg.Answers != "" {
+				answers, err := LoadAnswers(cfg.Answers)
+				if err != nil {
+					return err
+				}
+				ctx = ContextWithAnswers(ctx, answers)
+
+				// answers replace prompts, so there is no one to confirm
+				cfg.NonInteractive = true
+			}
+
+			log, err := openEventLog(cmd, cfg)
+			if err != nil {
+				return err
+			}
+			if log != nil {
+				defer log.Close()
+
+				log.Redact(cfg.SecretValues()...)
+				log.Event("command", eventlog.Fields{
+					"command": cmd.CommandPath(),
+					"args":    args,
+					"version": Version.Version,
+				})
+				defer func() {
+					fields := eventlog.Fields{"exit_code": ExitCode(returnedError)}
+					if returnedError != nil {
+						fields["error"] = returnedError.Error()
+					}
+
+					var stackerr stacktrace.Error
+					if errors.As(returnedError, &stackerr) {
+						fields["stack"] = stackerr.Stack
+					}
+					log.Event("exit", fields)
+				}()
+
+				ctx = eventlog.NewContext(ctx, log)
+			}
+
+			if runCLI := t.UI().RunCLI; runCLI != nil {
+				streams := Streams{
+					In:  cmd.InOrStdin(),
+					Out: cmd.OutOrStdout(),
+					Err: cmd.ErrOrStderr(),
+				}
+				return runCLIOutput(ctx, cmd, streams, runCLI)
+			}
+
+			if cfg.Output != "" {
+				// no prompts or release check output in scripts
+				cfg.NonInteractive = true
+				SkipReleaseCheck = true
+
+				return runOutput(ctx, cmd, t.UI().RunTUI)
+			}
+
+			var rec *ui.Recorder
+			if cfg.Record != "" {
+				f, err := os.OpenFile(cfg.Record, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+				if err != nil {
+					return UserError{Err: fmt.Errorf("opening recording: %w", err)}
+				}
+				defer f.Close()
+
+				rec = ui.NewRecorder(f)
+				if width, height, err := term.GetSize(os.Stdout.Fd()); err == nil && width > 0 && height > 0 {
+					rec.Width, rec.Height = width, height
+				}
+			}
 
 			drv, prg := ui.NewDriverTUI(ctx)
+			if rec != nil {
+				drv.Record(rec)
+				for _, secret := range cfg.SecretValues() {
+					drv.Replace(secret, "[redacted]")
+				}
+			}
 			defer func() {
 				// release/restore
 				drv.Program.Quit()
@@ -354,6 +859,11 @@ func NewCmd[T UIer](parent *cobra.Command, name string, fn func(*cobra.Command))
 				return err
 			}
 
+			if rec != nil {
+				if err := rec.Err(); err != nil {
+					return fmt.Errorf("writing recording: %w", err)
+				}
+			}
 			return nil
 		}
 
@@ -371,6 +881,66 @@ func NewCmd[T UIer](parent *cobra.Command, name string, fn func(*cobra.Command))
 	return cmd
 }
 
+// SkipLastCommandLog is the annotation of commands whose runs replace no
+// last command log, such as those reading it or run by other programs.
+const SkipLastCommandLog = "anchor_skip_last_command_log"
+
+// openEventLog returns the event log of a run of cmd, writing to the log file,
+// if any, and the last command log. A last command log that cannot be written
+// is left out, since it is only kept in case of later debugging.
+func openEventLog(cmd *cobra.Command, cfg *Config) (*eventlog.Log, error) {
+	var files logFiles
+	if cfg.LogFile != "" {
+		f, err := os.OpenFile(cfg.LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, UserError{Err: fmt.Errorf("opening log file: %w", err)}
+		}
+		files = append(files, f)
+	}
+
+	if path := cfg.LastCommandLogPath(); path != "" && !hasAnnotation(cmd, SkipLastCommandLog) {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err == nil {
+			if f, err := os.OpenFile(path, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, 0600); err == nil {
+				files = append(files, f)
+			}
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, nil
+	}
+	return eventlog.New(files), nil
+}
+
+func hasAnnotation(cmd *cobra.Command, key string) bool {
+	for ; cmd != nil; cmd = cmd.Parent() {
+		if _, ok := cmd.Annotations[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// logFiles writes to every file.
+type logFiles []*os.File
+
+func (fs logFiles) Write(p []byte) (int, error) {
+	for _, f := range fs {
+		if _, err := f.Write(p); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (fs logFiles) Close() error {
+	var errs []error
+	for _, f := range fs {
+		errs = append(errs, f.Close())
+	}
+	return errors.Join(errs...)
+}
+
 func NewTestCmd(cmd *cobra.Command) *cobra.Command {
 	return constructorByCommands[cmd]()
 }
@@ -7,8 +7,12 @@ import (
 
 	"github.com/anchordotdev/cli"
 	_ "github.com/anchordotdev/cli/auth"
+	"github.com/anchordotdev/cli/completion"
+	_ "github.com/anchordotdev/cli/config"
+	_ "github.com/anchordotdev/cli/debug"
 	_ "github.com/anchordotdev/cli/lcl"
 	_ "github.com/anchordotdev/cli/org"
+	"github.com/anchordotdev/cli/plugin"
 	_ "github.com/anchordotdev/cli/service"
 	_ "github.com/anchordotdev/cli/trust"
 	versionpkg "github.com/anchordotdev/cli/version"
@@ -29,6 +33,8 @@ func init() {
 		cli.Version.Version = version
 	}
 	cli.CmdRoot.PersistentPostRunE = versionpkg.ReleaseCheck
+
+	completion.Register(cli.CmdRoot)
 }
 
 func main() {
@@ -51,7 +57,11 @@ func main() {
 	ctx, cancel := context.WithCancel(cli.CmdRoot.Context())
 	defer cancel()
 
+	if ok, err := plugin.Dispatch(ctx, cli.CmdRoot, os.Args[1:]); ok || err != nil {
+		os.Exit(cli.ExitCode(err))
+	}
+
 	if err := cli.CmdRoot.ExecuteContext(ctx); err != nil {
-		os.Exit(1)
+		os.Exit(cli.ExitCode(err))
 	}
 }
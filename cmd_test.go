@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenEventLog(t *testing.T) {
+	t.Run("last command", func(t *testing.T) {
+		cfg := new(Config)
+		cfg.Test.CacheDir = t.TempDir()
+
+		log, err := openEventLog(&cobra.Command{Use: "lcl"}, cfg)
+		require.NoError(t, err)
+		require.NotNil(t, log)
+		require.NoError(t, log.Close())
+
+		require.FileExists(t, cfg.LastCommandLogPath())
+	})
+
+	t.Run("skipped by parent", func(t *testing.T) {
+		cfg := new(Config)
+		cfg.Test.CacheDir = t.TempDir()
+
+		parent := &cobra.Command{
+			Use:         "credential-helper",
+			Annotations: map[string]string{SkipLastCommandLog: ""},
+		}
+		cmd := &cobra.Command{Use: "get"}
+		parent.AddCommand(cmd)
+
+		log, err := openEventLog(cmd, cfg)
+		require.NoError(t, err)
+		require.Nil(t, log)
+
+		require.NoFileExists(t, cfg.LastCommandLogPath())
+	})
+}
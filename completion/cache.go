@@ -0,0 +1,92 @@
+package completion
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/api"
+	"github.com/anchordotdev/cli/keyring"
+)
+
+var (
+	// CacheTTL is how long fetched values are reused, since every completion
+	// runs in a new process.
+	CacheTTL = 30 * time.Second
+
+	// CacheDir overrides the user cache directory.
+	CacheDir string
+
+	// Timeout limits how long completion waits for the API.
+	Timeout = 3 * time.Second
+)
+
+type cacheEntry struct {
+	Time   time.Time          `json:"time"`
+	Values []cobra.Completion `json:"values"`
+}
+
+type fetchFunc func(context.Context, *api.Session) ([]cobra.Completion, error)
+
+// cached returns the completions for key from the cache, or fetches them. Any
+// error, such as being signed out, results in no completions.
+func cached(cfg *cli.Config, key string, fetch fetchFunc) []cobra.Completion {
+	path := cachePath(cfg, key)
+
+	if data, err := os.ReadFile(path); err == nil {
+		var entry cacheEntry
+		if err := json.Unmarshal(data, &entry); err == nil && time.Since(entry.Time) < CacheTTL {
+			return entry.Values
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	anc, err := api.NewClient(ctx, cfg)
+	if err != nil {
+		return nil
+	}
+
+	values, err := fetch(ctx, anc)
+	if err != nil {
+		return nil
+	}
+
+	if data, err := json.Marshal(cacheEntry{Time: time.Now(), Values: values}); err == nil {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err == nil {
+			_ = os.WriteFile(path, data, 0600)
+		}
+	}
+	return values
+}
+
+// cachePath is unique to the API and the token in use, so values are not
+// shared between accounts. The token is read from the keyring when it is not
+// configured.
+func cachePath(cfg *cli.Config, key string) string {
+	dir := CacheDir
+	if dir == "" {
+		userDir, err := os.UserCacheDir()
+		if err != nil {
+			userDir = os.TempDir()
+		}
+		dir = filepath.Join(userDir, "anchor", "completion")
+	}
+
+	token := cfg.API.Token
+	if token == "" {
+		kr := keyring.Keyring{Config: cfg}
+		token, _ = kr.Get(keyring.APIToken)
+	}
+
+	sum := sha256.Sum256([]byte(cfg.API.URL + "\x00" + token + "\x00" + key))
+	return filepath.Join(dir, hex.EncodeToString(sum[:12])+".json")
+}
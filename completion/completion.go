@@ -0,0 +1,147 @@
+// Package completion completes the values of common flags in shell completion,
+// fetching orgs, realms and services from the API.
+package completion
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/api"
+)
+
+// enumFlags are flags completed with the allowed values of a config key.
+var enumFlags = map[string]string{
+	"category":     "service.category",
+	"cert-style":   "service.cert-style",
+	"env-output":   "service.env-output",
+	"output":       "output",
+	"trust-stores": "trust.stores",
+}
+
+// Register adds completion functions to the org, realm, service and enumerated
+// flags of cmd and its subcommands.
+func Register(cmd *cobra.Command) {
+	register := func(name string, fn cobra.CompletionFunc) {
+		if cmd.LocalFlags().Lookup(name) != nil {
+			_ = cmd.RegisterFlagCompletionFunc(name, fn)
+		}
+	}
+
+	register("org", completeOrgs)
+	register("realm", completeRealms)
+	register("service", completeServices)
+
+	for name, key := range enumFlags {
+		if flag := cmd.LocalFlags().Lookup(name); flag != nil {
+			register(name, completeEnum(cli.ConfigEnums[key], flag.Value.Type() == "stringSlice"))
+		}
+	}
+
+	for _, sub := range cmd.Commands() {
+		Register(sub)
+	}
+}
+
+func completeEnum(values []string, list bool) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if !list || !strings.Contains(toComplete, ",") {
+			return values, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		// lists are comma separated, complete the last item
+		prefix := toComplete[:strings.LastIndex(toComplete, ",")+1]
+		chosen := strings.Split(prefix, ",")
+
+		var completions []cobra.Completion
+		for _, value := range values {
+			if !slices.Contains(chosen, value) {
+				completions = append(completions, prefix+value)
+			}
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+}
+
+func completeOrgs(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	cfg, ok := loadConfig(cmd)
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions := cached(cfg, "orgs", func(ctx context.Context, anc *api.Session) ([]cobra.Completion, error) {
+		orgs, err := anc.GetOrgs(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var completions []cobra.Completion
+		for _, org := range orgs {
+			completions = append(completions, cobra.CompletionWithDesc(org.Apid, org.Name))
+		}
+		return completions, nil
+	})
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeRealms(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	cfg, ok := loadConfig(cmd)
+	if !ok || cfg.Org.APID == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions := cached(cfg, "realms/"+cfg.Org.APID, func(ctx context.Context, anc *api.Session) ([]cobra.Completion, error) {
+		realms, err := anc.GetOrgRealms(ctx, cfg.Org.APID)
+		if err != nil {
+			return nil, err
+		}
+
+		var completions []cobra.Completion
+		for _, realm := range realms {
+			completions = append(completions, cobra.CompletionWithDesc(realm.Apid, realm.Name))
+		}
+		return completions, nil
+	})
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeServices(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	cfg, ok := loadConfig(cmd)
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// workspace services are known without signing in
+	var completions []cobra.Completion
+	for _, svc := range cfg.Services {
+		completions = append(completions, cobra.CompletionWithDesc(svc.Name, "workspace service"))
+	}
+
+	if cfg.Org.APID != "" {
+		completions = append(completions, cached(cfg, "services/"+cfg.Org.APID, func(ctx context.Context, anc *api.Session) ([]cobra.Completion, error) {
+			services, err := anc.GetOrgServices(ctx, cfg.Org.APID)
+			if err != nil {
+				return nil, err
+			}
+
+			var completions []cobra.Completion
+			for _, svc := range services {
+				completions = append(completions, cobra.CompletionWithDesc(svc.Slug, svc.Name))
+			}
+			return completions, nil
+		})...)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// loadConfig loads the config of cmd, including the flags typed so far.
+func loadConfig(cmd *cobra.Command) (*cli.Config, bool) {
+	cfg := new(cli.Config)
+	if err := cfg.Load(cmd.Context()); err != nil {
+		return nil, false
+	}
+	return cfg, true
+}
@@ -0,0 +1,116 @@
+package completion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/api"
+	"github.com/anchordotdev/cli/keyring"
+)
+
+func TestCompleteEnum(t *testing.T) {
+	values := []string{"homebrew", "nss", "system"}
+
+	t.Run("value", func(t *testing.T) {
+		got, directive := completeEnum(values, false)(nil, nil, "sys")
+		if want := values; !slices.Equal(want, got) {
+			t.Errorf("want completions %q, got %q", want, got)
+		}
+		if directive != cobra.ShellCompDirectiveNoFileComp {
+			t.Errorf("want no file completion, got directive %d", directive)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		got, directive := completeEnum(values, true)(nil, nil, "system,n")
+		if want := []string{"system,homebrew", "system,nss"}; !slices.Equal(want, got) {
+			t.Errorf("want completions %q, got %q", want, got)
+		}
+		if directive&cobra.ShellCompDirectiveNoSpace == 0 {
+			t.Errorf("want no space after list items, got directive %d", directive)
+		}
+	})
+}
+
+func TestCached(t *testing.T) {
+	CacheDir = t.TempDir()
+	t.Cleanup(func() { CacheDir = "" })
+
+	cfg := new(cli.Config)
+	cfg.API.URL = "https://api.anchor.dev"
+	cfg.Keyring.Backend = "file"
+	cfg.Keyring.File.Path = filepath.Join(t.TempDir(), "keyring")
+
+	fetch := func(context.Context, *api.Session) ([]cobra.Completion, error) {
+		return nil, errors.New("unexpected fetch")
+	}
+
+	write := func(t *testing.T, key string, entry cacheEntry) {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := cachePath(cfg, key)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("fresh", func(t *testing.T) {
+		write(t, "orgs", cacheEntry{Time: time.Now(), Values: []cobra.Completion{"org-apid"}})
+
+		if want, got := []cobra.Completion{"org-apid"}, cached(cfg, "orgs", fetch); !slices.Equal(want, got) {
+			t.Errorf("want cached completions %q, got %q", want, got)
+		}
+	})
+
+	t.Run("stale-signed-out", func(t *testing.T) {
+		write(t, "realms/org-apid", cacheEntry{Time: time.Now().Add(-2 * CacheTTL), Values: []cobra.Completion{"realm-apid"}})
+
+		if got := cached(cfg, "realms/org-apid", fetch); len(got) != 0 {
+			t.Errorf("want no completions when signed out, got %q", got)
+		}
+	})
+
+	t.Run("scoped-by-token", func(t *testing.T) {
+		other := *cfg
+		other.API.Token = "other-token"
+
+		if cachePath(cfg, "orgs") == cachePath(&other, "orgs") {
+			t.Errorf("want cache paths to differ by token")
+		}
+	})
+
+	t.Run("scoped-by-keyring-token", func(t *testing.T) {
+		signin := func(t *testing.T, token string) *cli.Config {
+			cfg := new(cli.Config)
+			cfg.API.URL = "https://api.anchor.dev"
+			cfg.Keyring.Backend = "file"
+			cfg.Keyring.File.Path = filepath.Join(t.TempDir(), "keyring")
+			cfg.Keyring.File.Passphrase = "test"
+
+			kr := keyring.Keyring{Config: cfg}
+			if err := kr.Set(keyring.APIToken, token); err != nil {
+				t.Fatal(err)
+			}
+			return cfg
+		}
+
+		cfg, other := signin(t, "ap0_first"), signin(t, "ap0_second")
+		if cachePath(cfg, "orgs") == cachePath(other, "orgs") {
+			t.Errorf("want cache paths to differ by keyring token")
+		}
+	})
+}
@@ -3,14 +3,23 @@ package component
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 
+	"github.com/anchordotdev/cli"
 	"github.com/anchordotdev/cli/component/models"
 	"github.com/anchordotdev/cli/ui"
 )
 
+// envByFlag is the environment variable providing the value of selector flags.
+var envByFlag = map[string]string{
+	"--org":     "ORG",
+	"--realm":   "REALM",
+	"--service": "SERVICE",
+}
+
 type Selector[T Choosable] struct {
 	Prompt string
 	Flag   string
@@ -51,6 +60,19 @@ func (s *Selector[T]) Choice(ctx context.Context, drv *ui.Driver) (*T, error) {
 		}
 	}
 
+	var t T
+	input := cli.Input{
+		Name: t.Singular(),
+		Flag: s.Flag,
+		Env:  envByFlag[s.Flag],
+		ID:   strings.TrimPrefix(s.Flag, "--"),
+	}
+	if answer, ok, err := cli.Answer(ctx, input); err != nil {
+		return nil, err
+	} else if ok {
+		return s.answerChoice(answer)
+	}
+
 	var choices []ui.ListItem[T]
 	for _, item := range s.Choices {
 		choice := ui.ListItem[T]{
@@ -64,7 +86,6 @@ func (s *Selector[T]) Choice(ctx context.Context, drv *ui.Driver) (*T, error) {
 		choices = append(choices, choice)
 	}
 	if s.Creatable {
-		var t T
 		choices = append(choices, ui.ListItem[T]{
 			String: fmt.Sprintf("Create New %s", cases.Title(language.English).String(t.Singular())),
 		})
@@ -87,3 +108,19 @@ func (s *Selector[T]) Choice(ctx context.Context, drv *ui.Driver) (*T, error) {
 		return nil, ctx.Err()
 	}
 }
+
+// answerChoice returns the choice with the key answer, or nil to create a new
+// item for AnswerNew.
+func (s *Selector[T]) answerChoice(answer string) (*T, error) {
+	if answer == cli.AnswerNew && s.Creatable {
+		return nil, nil
+	}
+	for _, choice := range s.Choices {
+		if choice.Key() == answer {
+			return &choice, nil
+		}
+	}
+
+	var t T
+	return nil, cli.UserError{Err: fmt.Errorf("answer %q for %s does not match any %s", answer, strings.TrimPrefix(s.Flag, "--"), t.Singular())}
+}
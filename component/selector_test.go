@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -249,3 +250,29 @@ func TestSelector(t *testing.T) {
 		uitest.TestGolden(t, drv.Golden())
 	})
 }
+
+func TestSelectorNonInteractive(t *testing.T) {
+	cfg := new(cli.Config)
+	cfg.NonInteractive = true
+	ctx := cli.ContextWithConfig(context.Background(), cfg)
+
+	selector := component.Selector[api.Organization]{
+		Prompt: "Which organization do you want for this test?",
+		Flag:   "--org",
+
+		Choices: []api.Organization{
+			{Apid: "first-org", Name: "First Org"},
+			{Apid: "second-org", Name: "Second Org"},
+		},
+	}
+
+	_, err := selector.Choice(ctx, nil)
+
+	var merr cli.MissingInputError
+	if !errors.As(err, &merr) {
+		t.Fatalf("want missing input error, got %v", err)
+	}
+	if want, got := []cli.Input{{Name: "organization", Flag: "--org", Env: "ORG"}}, merr.Inputs; !reflect.DeepEqual(want, got) {
+		t.Errorf("want missing inputs %+v, got %+v", want, got)
+	}
+}
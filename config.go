@@ -3,13 +3,18 @@ package cli
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"io/fs"
 	"net"
 	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
 	"runtime"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joeshaw/envdecode"
@@ -24,13 +29,37 @@ import (
 type ConfigFetchFunc func(*Config) any
 
 type Config struct {
+	Version int `toml:"version,omitempty,readonly"`
+
 	NonInteractive bool `env:"NON_INTERACTIVE" toml:",omitempty,readonly"`
 
+	Output  string `env:"ANCHOR_OUTPUT" toml:",omitempty,readonly"`
+	LogFile string `env:"ANCHOR_LOG" toml:",omitempty,readonly"`
+	Answers string `env:"ANCHOR_ANSWERS" toml:",omitempty,readonly"`
+	Record  string `env:"ANCHOR_RECORD" toml:",omitempty,readonly"`
+
 	API struct {
 		URL   string `default:"https://api.anchor.dev/v0" env:"API_URL" toml:"url,omitempty"`
-		Token string `env:"API_TOKEN" toml:"api-token,omitempty,readonly"`
+		Token string `env:"API_TOKEN" secret:"true" toml:"api-token,omitempty,readonly"`
 	} `toml:"api,omitempty"`
 
+	Auth struct {
+		Migrate struct {
+			To string `flag:"to" toml:",omitempty"`
+		} `toml:",omitempty"`
+
+		SignIn struct {
+			WithToken bool `flag:"with-token" toml:",omitempty"`
+		} `toml:",omitempty"`
+	} `toml:",omitempty,readonly"`
+
+	Config struct {
+		Show struct {
+			Format   string `default:"toml" flag:"format" toml:",omitempty"`
+			Validate bool   `flag:"validate" toml:",omitempty"`
+		} `toml:",omitempty"`
+	} `toml:",omitempty,readonly"`
+
 	File struct {
 		Path string `default:"anchor.toml" env:"ANCHOR_CONFIG" toml:",omitempty,readonly"`
 		Skip bool   `env:"ANCHOR_SKIP_CONFIG" toml:",omitempty,readonly"`
@@ -40,6 +69,12 @@ type Config struct {
 		URL string `default:"https://anchor.dev" env:"ANCHOR_HOST" toml:"url,omitempty"`
 	} `toml:"dashboard,omitempty"`
 
+	Debug struct {
+		Bundle struct {
+			File string `flag:"file" toml:",omitempty"`
+		} `toml:",omitempty"`
+	} `toml:",omitempty,readonly"`
+
 	Lcl struct {
 		LclHostURL string `default:"https://lcl.host" env:"LCL_HOST_URL" toml:",omitempty,readonly"`
 
@@ -54,6 +89,13 @@ type Config struct {
 			Domains []string `flag:"domains" toml:",omitempty"`
 			SubCa   string   `flag:"subca" toml:",omitempty"`
 		} `toml:",omitempty,readonly"`
+
+		Proxy struct {
+			To           string `env:"PROXY_TO" flag:"to" toml:",omitempty"`
+			Domain       string `env:"PROXY_DOMAIN" flag:"domain" toml:",omitempty"`
+			Listen       string `default:":4443" env:"PROXY_LISTEN" flag:"listen" toml:",omitempty"`
+			RedirectHTTP bool   `flag:"redirect-http" toml:",omitempty"`
+		} `toml:",omitempty,readonly"`
 	} `toml:"lcl-host,omitempty"`
 
 	Org struct {
@@ -73,12 +115,17 @@ type Config struct {
 
 		EnvOutput string `env:"ENV_OUTPUT" toml:",omitempty,readonly"`
 		CertStyle string `env:"CERT_STYLE" toml:"cert-style,omitempty"`
+		Server    string `env:"SERVICE_SERVER" toml:",omitempty,readonly"`
+
+		Domains []string `toml:",omitempty,readonly"`
 
 		Verify struct {
 			Timeout time.Duration `default:"2m" env:"VERIFY_TIMEOUT" toml:",omitempty,readonly"`
 		} `toml:",omitempty,readonly"`
 	} `toml:"service,omitempty"`
 
+	Services []ServiceConfig `toml:"services,omitempty"`
+
 	Trust struct {
 		NoSudo bool `flag:"no-sudo" env:"NO_SUDO" toml:",omitempty"`
 
@@ -92,18 +139,49 @@ type Config struct {
 	} `toml:",omitempty,readonly"`
 
 	Keyring struct {
-		MockMode bool `env:"ANCHOR_CLI_KEYRING_MOCK_MODE"`
-	} `toml:",omitempty,readonly"`
+		Backend string `default:"system" env:"ANCHOR_KEYRING_BACKEND" toml:"backend,omitempty,readonly" useronly:"true"`
+		Helper  string `env:"ANCHOR_KEYRING_HELPER" toml:"helper,omitempty,readonly" useronly:"true"`
+
+		File struct {
+			Path       string `env:"ANCHOR_KEYRING_FILE" toml:"path,omitempty,readonly" useronly:"true"`
+			Passphrase string `env:"ANCHOR_KEYRING_PASSPHRASE" secret:"true" toml:",omitempty,readonly" useronly:"true"`
+		} `toml:"file,omitempty"`
+
+		MockMode bool `env:"ANCHOR_CLI_KEYRING_MOCK_MODE" toml:",omitempty,readonly"`
+	} `toml:"keyring,omitempty"`
+
+	Plugin struct {
+		// PathToken passes the API token to plugins found on PATH too, rather
+		// than only to those in the plugins directory.
+		PathToken bool `env:"ANCHOR_PLUGIN_PATH_TOKEN" toml:"path-token,omitempty,readonly" useronly:"true"`
+	} `toml:"plugin,omitempty"`
 
 	Test ConfigTest `fake:"-" toml:",omitempty,readonly"`
 
 	Via struct {
 		Defaults *Config `fake:"-" toml:",omitempty,readonly"`
 		ENV      *Config `fake:"-" toml:",omitempty,readonly"`
+		Flags    *Config `fake:"-" toml:",omitempty,readonly"`
 		TOML     *Config `fake:"-" toml:",omitempty,readonly"`
+
+		UserTOML   *Config `fake:"-" toml:",omitempty,readonly"`
+		SystemTOML *Config `fake:"-" toml:",omitempty,readonly"`
 	} `toml:",omitempty,readonly"`
 }
 
+// ServiceConfig is a single service of a multi-service anchor.toml workspace,
+// configured as a [[services]] entry.
+type ServiceConfig struct {
+	Name      string   `toml:"name"`
+	APID      string   `toml:"apid,omitempty"`
+	Category  string   `toml:"category,omitempty"`
+	CertStyle string   `toml:"cert-style,omitempty"`
+	Domains   []string `toml:"domains,omitempty"`
+
+	CertFile string `toml:"cert-file,omitempty"` // certificate chain output path
+	KeyFile  string `toml:"key-file,omitempty"`  // private key output path
+}
+
 type Dialer interface {
 	DialContext(context.Context, string, string) (net.Conn, error)
 }
@@ -122,14 +200,19 @@ type ConfigTest struct {
 		URL string
 	}
 	Browserless bool          // run as though browserless
+	CacheDir    string        // change the user cache directory in tests
 	GOOS        string        // change OS identifier in tests
 	ProcFS      fs.FS         // change the proc filesystem in tests
 	LclHostPort int           // specify lcl host port in tests
 	SkipRunE    bool          // skip RunE for testing purposes
 	SystemFS    SystemFS      // change the system filesystem in tests
+	SystemTOML  string        // change the system config file path in tests
+	UserTOML    string        // change the user config file path in tests
 	Timestamp   time.Time     // timestamp to use/display in tests
 	NetResolver *net.Resolver // DNS resolver for (some) tests
 	NetDialer   Dialer        // TCP dialer for (some) tests
+
+	ConfigMigrations []ConfigMigration // replace ConfigMigrations in tests
 }
 
 type ConfigTestPrefer struct {
@@ -196,11 +279,57 @@ func (c *Config) Load(ctx context.Context) error {
 	}
 
 	if cfg := ConfigFromContext(ctx); cfg != nil {
-		return c.setNonDefaults(cfg)
+		if err := c.setNonDefaults(cfg); err != nil {
+			return err
+		}
+		c.Via.Flags = cfg
 	}
+
+	c.selectService()
 	return nil
 }
 
+// WorkspaceService returns the [[services]] entry matching name by name or
+// apid, or the only entry when name is empty.
+func (c *Config) WorkspaceService(name string) (*ServiceConfig, bool) {
+	if name == "" {
+		if len(c.Services) == 1 {
+			return &c.Services[0], true
+		}
+		return nil, false
+	}
+
+	for i := range c.Services {
+		if svc := &c.Services[i]; svc.Name == name || (svc.APID != "" && svc.APID == name) {
+			return svc, true
+		}
+	}
+	return nil, false
+}
+
+// selectService fills Service from the workspace service selected by
+// --service, without overriding values set elsewhere.
+func (c *Config) selectService() {
+	svc, ok := c.WorkspaceService(c.Service.APID)
+	if !ok {
+		return
+	}
+
+	c.Service.APID = svc.APID
+	if c.Service.Name == "" {
+		c.Service.Name = svc.Name
+	}
+	if c.Service.Category == "" {
+		c.Service.Category = svc.Category
+	}
+	if c.Service.CertStyle == "" {
+		c.Service.CertStyle = svc.CertStyle
+	}
+	if len(c.Service.Domains) == 0 {
+		c.Service.Domains = svc.Domains
+	}
+}
+
 func (c *Config) ProcFS() fs.FS {
 	if procFS := c.Test.ProcFS; procFS != nil {
 		return procFS
@@ -222,12 +351,65 @@ func (c *Config) Timestamp() time.Time {
 	return time.Now().UTC()
 }
 
+// WriteTOML stores the config in the config file. An existing file is migrated
+// to the current version and edited in place: only changed keys are rewritten,
+// and comments, layout and unknown keys are preserved.
 func (c *Config) WriteTOML() error {
-	var buf bytes.Buffer
-	if err := c.encodeTOML(&buf); err != nil {
+	doc, err := fs.ReadFile(c.SystemFS(), c.File.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		var buf bytes.Buffer
+		if err := c.encodeTOML(&buf); err != nil {
+			return err
+		}
+
+		data := fmt.Appendf(nil, "version = %d\n", LatestConfigVersion(c.Migrations()))
+		if buf.Len() > 0 {
+			data = append(append(data, '\n'), buf.Bytes()...)
+		}
+		return WriteConfigFile(c.SystemFS(), c.File.Path, data, 0644)
+	}
+	if err != nil {
+		return err
+	}
+
+	if doc, _, err = c.migrateTOMLFile(c.File.Path, doc); err != nil {
 		return err
 	}
-	return c.SystemFS().WriteFile(c.File.Path, buf.Bytes(), 0644)
+
+	fileCfg := *Defaults
+	if err := toml.NewDecoder(bytes.NewReader(doc)).Decode(&fileCfg); err != nil {
+		return UserError{Err: fmt.Errorf("invalid config file %s, %s", c.File.Path, toml.DescribeError(err))}
+	}
+
+	var old, cfg Config
+	if err := old.setNonDefaults(&fileCfg); err != nil {
+		return err
+	}
+	if err := cfg.setNonDefaults(c); err != nil {
+		return err
+	}
+
+	data, err := toml.Edit(doc, old, cfg)
+	if err != nil {
+		return UserError{Err: fmt.Errorf("config file %s was not changed, %w, please edit it by hand", c.File.Path, err)}
+	}
+	return WriteConfigFile(c.SystemFS(), c.File.Path, data, 0644)
+}
+
+// ReadTOML returns the defaults combined with only the values stored in the
+// config file, ignoring env and flags. A missing config file is not an error.
+func (c *Config) ReadTOML() (*Config, error) {
+	cfg, err := c.decodeTOMLFile(c.SystemFS(), c.File.Path)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		cfg = Defaults.Copy()
+	}
+	cfg.File = c.File
+	cfg.Test.SystemFS = c.Test.SystemFS
+
+	return cfg, nil
 }
 
 func (c *Config) loadDefaults() error {
@@ -276,21 +458,235 @@ func (c *Config) loadTOML(fsys fs.FS) error {
 		return nil
 	}
 
-	if f, err := fsys.Open(c.File.Path); err == nil {
-		cfg := *Defaults
-		if err := toml.NewDecoder(f).Decode(&cfg); err != nil {
+	// lowest to highest precedence: system, user, then project config
+	if path := c.SystemConfigPath(); path != "" {
+		cfg, err := c.decodeTOMLFile(fsys, path)
+		if err != nil {
 			return err
 		}
-		if err := c.setNonDefaults(&cfg); err != nil {
+		if cfg != nil {
+			if err := c.setNonDefaults(cfg); err != nil {
+				return err
+			}
+			c.Via.SystemTOML = cfg
+		}
+	}
+
+	if path := c.UserConfigPath(); path != "" {
+		cfg, err := c.decodeTOMLFile(fsys, path)
+		if err != nil {
 			return err
 		}
-		c.Via.TOML = &cfg
-	} else if c.File.Path != Defaults.File.Path {
+		if cfg != nil {
+			if err := c.setNonDefaults(cfg); err != nil {
+				return err
+			}
+			c.Via.UserTOML = cfg
+		}
+	}
+
+	_, explicit := os.LookupEnv("ANCHOR_CONFIG")
+	if !explicit && !fs.Changed("config") {
+		if path, ok := discoverTOML(fsys, c.File.Path); ok {
+			c.File.Path = path
+		}
+	}
+
+	cfg, err := c.decodeTOMLFile(fsys, c.File.Path)
+	if err != nil {
 		return err
 	}
+	if cfg != nil {
+		// the project config is discovered in parent directories, so it may not
+		// be trusted to pick the commands and files that hold credentials
+		for _, field := range ConfigFields {
+			if field.UserOnly {
+				cfg.ResetField(field)
+			}
+		}
+
+		if err := c.setNonDefaults(cfg); err != nil {
+			return err
+		}
+		c.Via.TOML = cfg
+	} else if c.File.Path != Defaults.File.Path {
+		return &os.PathError{Op: "open", Path: c.File.Path, Err: os.ErrNotExist}
+	}
 	return nil
 }
 
+// UserConfigPath returns the path of the per-user config file, which is
+// $XDG_CONFIG_HOME/anchor/config.toml or the platform equivalent.
+func (c *Config) UserConfigPath() string {
+	if path := c.Test.UserTOML; path != "" {
+		return path
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		var err error
+		if dir, err = os.UserConfigDir(); err != nil {
+			return ""
+		}
+	}
+	return filepath.Join(dir, "anchor", "config.toml")
+}
+
+// SetUserTOML sets the dotted keys of values in the user config file, editing
+// it in place or creating it. The keys are written together, or not at all.
+// Unlike WriteTOML, it can store keys that are readonly in the project config,
+// such as keyring.backend.
+func (c *Config) SetUserTOML(values map[string]any) error {
+	path := c.UserConfigPath()
+	if path == "" {
+		return errors.New("unable to locate user config directory")
+	}
+
+	data, err := fs.ReadFile(c.SystemFS(), path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	var doc map[string]any
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return UserError{Err: fmt.Errorf("invalid config file %s, %s", path, toml.DescribeError(err))}
+	}
+	if doc == nil {
+		doc = make(map[string]any)
+	}
+	old := deepcopy.Copy(doc).(map[string]any)
+
+	for key, value := range values {
+		table, parts := doc, strings.Split(key, ".")
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := table[part].(map[string]any)
+			if !ok {
+				next = make(map[string]any)
+				table[part] = next
+			}
+			table = next
+		}
+		table[parts[len(parts)-1]] = value
+	}
+
+	out, err := toml.Edit(data, old, doc)
+	if err != nil {
+		return UserError{Err: fmt.Errorf("config file %s was not changed, %w, please edit it by hand", path, err)}
+	}
+
+	if _, ok := c.SystemFS().(osFS); ok {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return err
+		}
+	}
+	return WriteConfigFile(c.SystemFS(), path, out, 0644)
+}
+
+// PluginDir returns the directory searched for plugins before PATH, which is
+// plugins alongside the user config file.
+func (c *Config) PluginDir() string {
+	path := c.UserConfigPath()
+	if path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(path), "plugins")
+}
+
+// LastCommandLogPath returns the path of the event log of the last command run,
+// kept in the user cache directory for `anchor debug bundle`.
+func (c *Config) LastCommandLogPath() string {
+	dir := c.Test.CacheDir
+	if dir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(cacheDir, "anchor")
+	}
+	return filepath.Join(dir, "last-command.log")
+}
+
+// SystemConfigPath returns the path of the optional system-wide config file.
+func (c *Config) SystemConfigPath() string {
+	if path := c.Test.SystemTOML; path != "" {
+		return path
+	}
+	if c.GOOS() == "windows" {
+		dir := os.Getenv("ProgramData")
+		if dir == "" {
+			return ""
+		}
+		return filepath.Join(dir, "anchor", "config.toml")
+	}
+	return "/etc/anchor/config.toml"
+}
+
+// discoverTOML looks for name in the current directory and, when inside a git
+// repository, each parent directory up to the repository root.
+func discoverTOML(fsys fs.FS, name string) (string, bool) {
+	if filepath.IsAbs(name) || filepath.Base(name) != name {
+		return "", false
+	}
+
+	dirs := []string{"."}
+	for dir := "."; ; {
+		if _, err := fs.Stat(fsys, filepath.Join(dir, ".git")); err == nil {
+			break
+		}
+
+		abs, err := filepath.Abs(dir)
+		if err != nil || filepath.Dir(abs) == abs {
+			dirs = dirs[:1] // not in a repository, only check the current directory
+			break
+		}
+
+		dir = filepath.Join(dir, "..")
+		dirs = append(dirs, dir)
+	}
+
+	for _, dir := range dirs {
+		path := filepath.Join(dir, name)
+		if _, err := fs.Stat(fsys, path); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// decodeTOMLFile returns the defaults combined with the values in the file at
+// path, or nil if it does not exist.
+func (c *Config) decodeTOMLFile(fsys fs.FS, path string) (*Config, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, version, err := c.migrateTOMLFile(path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := Defaults.Copy()
+	if err := toml.NewDecoder(bytes.NewReader(data)).Decode(cfg); err != nil {
+		return nil, UserError{Err: fmt.Errorf("invalid config file %s, %s", path, toml.DescribeError(err))}
+	}
+	cfg.Version = version // as written, before migration
+	return cfg, nil
+}
+
+func (c *Config) migrateTOMLFile(path string, data []byte) ([]byte, int, error) {
+	migrated, version, err := MigrateTOML(data, c.Migrations())
+	if errors.Is(err, ErrConfigVersion) {
+		return nil, 0, UserError{Err: fmt.Errorf("config file %s was written by a newer version of the anchor CLI (config version %d, this CLI supports up to %d), please update", path, version, LatestConfigVersion(c.Migrations()))}
+	}
+	if err != nil {
+		return nil, 0, UserError{Err: fmt.Errorf("invalid config file %s, %s", path, toml.DescribeError(err))}
+	}
+	return migrated, version, nil
+}
+
 func (c *Config) setNonDefaults(other *Config) error {
 	changeLog, err := diff.Diff(Defaults, other)
 	if err != nil {
@@ -301,21 +697,45 @@ func (c *Config) setNonDefaults(other *Config) error {
 	return nil
 }
 
-func (c *Config) ViaSource(fetcher func(*Config) any) string {
+// ViaSource reports where the value returned by fetcher was set: env, a config
+// file path, default or flag.
+func (c *Config) ViaSource(fetcher ConfigFetchFunc) string {
 	value := fetcher(c)
 
-	if fetcher(c.Via.ENV) == value {
-		return "env"
+	defaults := c.Via.Defaults
+	if defaults == nil {
+		defaults = Defaults
 	}
+	defaultValue := fetcher(defaults)
 
-	if c.Via.TOML != nil && fetcher(c.Via.TOML) == value {
-		return c.File.Path
+	// env is decoded onto an empty config, so only non-zero values were set
+	if c.Via.ENV != nil {
+		if envValue := fetcher(c.Via.ENV); envValue != nil && !reflect.ValueOf(envValue).IsZero() && reflect.DeepEqual(envValue, value) {
+			return "env"
+		}
 	}
 
-	if fetcher(c.Via.Defaults) == value {
-		return "default"
+	// config files, highest precedence first, only when they set a value
+	files := []struct {
+		via  *Config
+		path func() string
+	}{
+		{c.Via.TOML, func() string { return c.File.Path }},
+		{c.Via.UserTOML, c.UserConfigPath},
+		{c.Via.SystemTOML, c.SystemConfigPath},
+	}
+	for _, file := range files {
+		if file.via == nil {
+			continue
+		}
+		if tomlValue := fetcher(file.via); !reflect.DeepEqual(tomlValue, defaultValue) && reflect.DeepEqual(tomlValue, value) {
+			return file.path()
+		}
 	}
 
+	if reflect.DeepEqual(defaultValue, value) {
+		return "default"
+	}
 	return "flag"
 }
 
@@ -335,3 +755,50 @@ func (osFS) Stat(name string) (fs.FileInfo, error)      { return os.Stat(name) }
 func (osFS) WriteFile(name string, data []byte, perm os.FileMode) error {
 	return os.WriteFile(name, data, perm)
 }
+
+// WriteConfigFile replaces the config file name with data, writing to a
+// temporary file renamed into place, so readers never observe a partially
+// written file. Symlinks are followed, and an existing file keeps its mode and,
+// where permitted, its owner.
+func WriteConfigFile(fsys SystemFS, name string, data []byte, perm os.FileMode) error {
+	if _, ok := fsys.(osFS); !ok {
+		return fsys.WriteFile(name, data, perm)
+	}
+
+	if target, err := filepath.EvalSymlinks(name); err == nil {
+		name = target
+	}
+
+	fi, err := os.Stat(name)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if fi != nil {
+		perm = fi.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil && runtime.GOOS != "windows" {
+		tmp.Close()
+		return err
+	}
+	if fi != nil {
+		if err := keepOwner(tmp, fi); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
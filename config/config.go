@@ -0,0 +1,9 @@
+package config
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+)
+
+var CmdConfig = cli.NewCmd[cli.ShowHelp](cli.CmdRoot, "config", func(cmd *cobra.Command) {})
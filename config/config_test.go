@@ -0,0 +1,17 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/anchordotdev/cli/cmdtest"
+)
+
+func TestCmdConfig(t *testing.T) {
+	t.Run("config", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdConfig, "config")
+	})
+
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdConfig, "config", "--help")
+	})
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+)
+
+var CmdConfigGet = cli.NewCmd[Get](CmdConfig, "get", func(cmd *cobra.Command) {})
+
+type Get struct{}
+
+func (c Get) UI() cli.UI {
+	return cli.UI{
+		RunCLI: c.runCLI,
+	}
+}
+
+func (c *Get) runCLI(ctx context.Context, streams cli.Streams) error {
+	cfg := cli.ConfigFromContext(ctx)
+
+	field, err := fieldFromArgs(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(streams.Out, formatValue(cfg.DisplayValue(field)))
+	return err
+}
+
+func fieldFromArgs(ctx context.Context) (cli.ConfigField, error) {
+	args := cli.ArgsFromContext(ctx)
+	if len(args) == 0 {
+		return cli.ConfigField{}, cli.UserError{Err: fmt.Errorf("missing config key")}
+	}
+
+	field, ok := cli.ConfigFieldByKey(args[0])
+	if !ok {
+		return cli.ConfigField{}, cli.UserError{Err: fmt.Errorf("unknown config key %q, run `anchor config show` to list keys", args[0])}
+	}
+	return field, nil
+}
+
+func formatValue(value any) string {
+	switch value := value.(type) {
+	case []string:
+		return strings.Join(value, ",")
+	default:
+		return fmt.Sprint(value)
+	}
+}
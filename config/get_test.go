@@ -0,0 +1,41 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/cmdtest"
+)
+
+func TestCmdConfigGet(t *testing.T) {
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdConfigGet, "config", "get", "--help")
+	})
+}
+
+func TestGet(t *testing.T) {
+	cfg := cmdtest.Config(context.Background())
+	cfg.Service.CertStyle = "acme"
+	cfg.API.Token = "ap0_s3cr3t"
+	ctx := cli.ContextWithConfig(context.Background(), cfg)
+
+	tests := map[string]string{
+		"service.cert-style": "acme\n",
+		"trust.stores":       "homebrew,nss,system\n",
+		"api.api-token":      "[redacted]\n",
+	}
+
+	for key, want := range tests {
+		t.Run(key, func(t *testing.T) {
+			var out bytes.Buffer
+
+			cmd := Get{}
+			require.NoError(t, cmd.UI().RunCLI(cli.ContextWithArgs(ctx, []string{key}), cli.Streams{Out: &out}))
+			require.Equal(t, want, out.String())
+		})
+	}
+}
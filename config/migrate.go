@@ -0,0 +1,57 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+)
+
+var CmdConfigMigrate = cli.NewCmd[Migrate](CmdConfig, "migrate", func(cmd *cobra.Command) {})
+
+type Migrate struct{}
+
+func (c Migrate) UI() cli.UI {
+	return cli.UI{
+		RunCLI: c.runCLI,
+	}
+}
+
+func (c *Migrate) runCLI(ctx context.Context, streams cli.Streams) error {
+	cfg := cli.ConfigFromContext(ctx)
+
+	data, err := fs.ReadFile(cfg.SystemFS(), cfg.File.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cli.UserError{Err: fmt.Errorf("config file %s not found", cfg.File.Path)}
+	}
+	if err != nil {
+		return err
+	}
+
+	migrations := cfg.Migrations()
+	latest := cli.LatestConfigVersion(migrations)
+
+	migrated, version, err := cli.MigrateTOML(data, migrations)
+	if err != nil {
+		return err
+	}
+
+	if version == latest {
+		_, err := fmt.Fprintf(streams.Out, "%s is up to date (version %d).\n", cfg.File.Path, latest)
+		return err
+	}
+
+	if err := cli.WriteConfigFile(cfg.SystemFS(), cfg.File.Path, migrated, 0644); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(streams.Out, "Migrated %s to version %d.\n", cfg.File.Path, latest)
+	for _, migration := range cli.PendingConfigMigrations(migrations, version) {
+		fmt.Fprintf(streams.Out, "  - %s\n", migration.Description)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/clitest"
+	"github.com/anchordotdev/cli/cmdtest"
+)
+
+func TestCmdConfigMigrate(t *testing.T) {
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdConfigMigrate, "config", "migrate", "--help")
+	})
+}
+
+func TestMigrate(t *testing.T) {
+	migrations := []cli.ConfigMigration{
+		{
+			Version:     2,
+			Description: "rename service.lang to service.category",
+
+			Migrate: func(doc map[string]any) {
+				service := doc["service"].(map[string]any)
+				service["category"] = service["lang"]
+				delete(service, "lang")
+			},
+		},
+	}
+
+	cfg := new(cli.Config)
+	cfg.Test.ConfigMigrations = migrations
+	cfg.Test.SystemFS = clitest.TestFS{
+		"anchor.toml": &fstest.MapFile{
+			Data: []byte(heredoc.Doc(`
+				[service]
+				lang = 'ruby' # detected
+			`)),
+		},
+	}
+	require.NoError(t, cfg.Load(context.Background()))
+	ctx := cli.ContextWithConfig(context.Background(), cfg)
+
+	var out bytes.Buffer
+	cmd := Migrate{}
+	require.NoError(t, cmd.UI().RunCLI(ctx, cli.Streams{Out: &out}))
+
+	require.Equal(t, heredoc.Doc(`
+		Migrated anchor.toml to version 2.
+		  - rename service.lang to service.category
+	`), out.String())
+
+	require.Equal(t, heredoc.Doc(`
+		version = 2
+
+		[service]
+		category = 'ruby'
+	`), string(cfg.Test.SystemFS.(clitest.TestFS)["anchor.toml"].Data))
+
+	out.Reset()
+	require.NoError(t, cmd.UI().RunCLI(ctx, cli.Streams{Out: &out}))
+	require.Equal(t, "anchor.toml is up to date (version 2).\n", out.String())
+}
+
+func TestMigrateUnversioned(t *testing.T) {
+	cfg := new(cli.Config)
+	cfg.Test.SystemFS = clitest.TestFS{
+		"anchor.toml": &fstest.MapFile{
+			Data: []byte(heredoc.Doc(`
+				[service]
+				category = 'ruby'
+			`)),
+		},
+	}
+	require.NoError(t, cfg.Load(context.Background()))
+	ctx := cli.ContextWithConfig(context.Background(), cfg)
+
+	var out bytes.Buffer
+	cmd := Migrate{}
+	require.NoError(t, cmd.UI().RunCLI(ctx, cli.Streams{Out: &out}))
+	require.Equal(t, "Migrated anchor.toml to version 1.\n", out.String())
+
+	require.Equal(t, heredoc.Doc(`
+		version = 1
+
+		[service]
+		category = 'ruby'
+	`), string(cfg.Test.SystemFS.(clitest.TestFS)["anchor.toml"].Data))
+
+	out.Reset()
+	require.NoError(t, cmd.UI().RunCLI(ctx, cli.Streams{Out: &out}))
+	require.Equal(t, "anchor.toml is up to date (version 1).\n", out.String())
+}
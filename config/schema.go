@@ -0,0 +1,176 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fatih/structtag"
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+)
+
+const schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var CmdConfigSchema = cli.NewCmd[Schema](CmdConfig, "schema", func(cmd *cobra.Command) {})
+
+type Schema struct{}
+
+func (c Schema) UI() cli.UI {
+	return cli.UI{
+		RunCLI: c.runCLI,
+	}
+}
+
+func (c *Schema) runCLI(ctx context.Context, streams cli.Streams) error {
+	enc := json.NewEncoder(streams.Out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(JSONSchema())
+}
+
+// SchemaNode is a JSON Schema (sub)document, limited to the keywords needed to
+// describe anchor.toml.
+type SchemaNode struct {
+	Schema      string `json:"$schema,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Type    string   `json:"type,omitempty"`
+	Enum    []string `json:"enum,omitempty"`
+	Default any      `json:"default,omitempty"`
+	Minimum *int     `json:"minimum,omitempty"`
+	Maximum *int     `json:"maximum,omitempty"`
+
+	Properties           map[string]*SchemaNode `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Items                *SchemaNode            `json:"items,omitempty"`
+
+	Defs map[string]*SchemaNode `json:"$defs,omitempty"`
+}
+
+// schemaDefs are the enums shared by more than one key. Trust stores are
+// read-only in anchor.toml, they are only included for reference by other
+// tools.
+var schemaDefs = map[string]string{
+	"category":    "service.category",
+	"cert-style":  "service.cert-style",
+	"trust-store": "trust.stores",
+}
+
+// JSONSchema describes the writable keys of anchor.toml, as reflected from
+// cli.Config, so that it always matches the keys and values of this build.
+func JSONSchema() *SchemaNode {
+	root := objectSchema()
+	root.Schema = schemaDraft
+	root.Title = "anchor.toml"
+	root.Description = "Configuration file of the anchor CLI."
+
+	minVersion, maxVersion := 1, cli.ConfigVersion
+	root.Properties["version"] = &SchemaNode{
+		Description: "Layout version of this file, upgraded by `anchor config migrate`.",
+		Type:        "integer",
+		Minimum:     &minVersion,
+		Maximum:     &maxVersion,
+	}
+
+	for _, field := range cli.ConfigFields {
+		if field.ReadOnly {
+			continue
+		}
+
+		path := strings.Split(field.Key, ".")
+		parent := root
+		for _, key := range path[:len(path)-1] {
+			if parent.Properties[key] == nil {
+				parent.Properties[key] = objectSchema()
+			}
+			parent = parent.Properties[key]
+		}
+
+		prop := fieldSchema(field.Key, field.Type)
+		if value := cli.Defaults.FieldValue(field); !reflect.ValueOf(value).IsZero() {
+			prop.Default = value
+		}
+		if field.Env != "" {
+			prop.Description = fmt.Sprintf("Overridden by the %s environment variable.", field.Env)
+		}
+		parent.Properties[path[len(path)-1]] = prop
+	}
+
+	root.Properties["services"] = &SchemaNode{
+		Description: "Services of the workspace, provisioned by `anchor lcl up`.",
+		Type:        "array",
+		Items:       servicesSchema(),
+	}
+
+	root.Defs = make(map[string]*SchemaNode, len(schemaDefs))
+	for name, key := range schemaDefs {
+		root.Defs[name] = &SchemaNode{Type: "string", Enum: cli.ConfigEnums[key]}
+	}
+
+	return root
+}
+
+func objectSchema() *SchemaNode {
+	return &SchemaNode{
+		Type:                 "object",
+		Properties:           make(map[string]*SchemaNode),
+		AdditionalProperties: new(bool),
+	}
+}
+
+func fieldSchema(key string, typ reflect.Type) *SchemaNode {
+	for name, defKey := range schemaDefs {
+		if key == defKey {
+			return &SchemaNode{Ref: "#/$defs/" + name}
+		}
+	}
+
+	if typ == reflect.TypeOf(time.Duration(0)) {
+		return &SchemaNode{Type: "string"}
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return &SchemaNode{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &SchemaNode{Type: "integer"}
+	case reflect.Slice:
+		return &SchemaNode{Type: "array", Items: fieldSchema(key, typ.Elem())}
+	default:
+		return &SchemaNode{Type: "string", Enum: cli.ConfigEnums[key]}
+	}
+}
+
+// servicesSchema describes a [[services]] entry, which share the category and
+// cert-style values of [service].
+func servicesSchema() *SchemaNode {
+	item := objectSchema()
+
+	typ := reflect.TypeOf(cli.ServiceConfig{})
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+
+		tags, err := structtag.Parse(string(sf.Tag))
+		if err != nil {
+			panic(err)
+		}
+		tag, err := tags.Get("toml")
+		if err != nil {
+			continue
+		}
+
+		item.Properties[tag.Name] = fieldSchema("service."+tag.Name, sf.Type)
+		if !slices.Contains(tag.Options, "omitempty") {
+			item.Required = append(item.Required, tag.Name)
+		}
+	}
+	return item
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/cmdtest"
+	"github.com/anchordotdev/cli/truststore"
+)
+
+func TestCmdConfigSchema(t *testing.T) {
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdConfigSchema, "config", "schema", "--help")
+	})
+}
+
+func TestSchema(t *testing.T) {
+	ctx := cli.ContextWithConfig(context.Background(), new(cli.Config))
+
+	var out bytes.Buffer
+	cmd := Schema{}
+	require.NoError(t, cmd.UI().RunCLI(ctx, cli.Streams{Out: &out}))
+
+	var schema SchemaNode
+	require.NoError(t, json.Unmarshal(out.Bytes(), &schema))
+
+	require.Equal(t, schemaDraft, schema.Schema)
+	require.Equal(t, cli.ConfigVersion, *schema.Properties["version"].Maximum)
+
+	t.Run("readonly-skipped", func(t *testing.T) {
+		require.NotContains(t, schema.Properties, "trust")
+		require.NotContains(t, schema.Properties["service"].Properties, "env-output")
+		require.NotContains(t, schema.Properties, "keyring")
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		apiURL := schema.Properties["api"].Properties["url"]
+		require.Equal(t, "string", apiURL.Type)
+		require.Equal(t, cli.Defaults.API.URL, apiURL.Default)
+		require.Equal(t, "Overridden by the API_URL environment variable.", apiURL.Description)
+	})
+
+	t.Run("enums", func(t *testing.T) {
+		require.Equal(t, "#/$defs/category", schema.Properties["service"].Properties["category"].Ref)
+		require.Equal(t, cli.ConfigEnums["service.category"], schema.Defs["category"].Enum)
+		require.Equal(t, cli.ConfigEnums["service.cert-style"], schema.Defs["cert-style"].Enum)
+		require.Equal(t, cli.ConfigEnums["trust.stores"], schema.Defs["trust-store"].Enum)
+		require.Contains(t, schema.Defs["trust-store"].Enum, truststore.StoreMock)
+	})
+
+	t.Run("services", func(t *testing.T) {
+		item := schema.Properties["services"].Items
+		require.Equal(t, []string{"name"}, item.Required)
+		require.Equal(t, "#/$defs/cert-style", item.Properties["cert-style"].Ref)
+		require.Equal(t, "array", item.Properties["domains"].Type)
+		require.Equal(t, "string", item.Properties["key-file"].Type)
+	})
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+)
+
+var (
+	CmdConfigSet = cli.NewCmd[Set](CmdConfig, "set", func(cmd *cobra.Command) {})
+
+	CmdConfigUnset = cli.NewCmd[Unset](CmdConfig, "unset", func(cmd *cobra.Command) {})
+)
+
+type Set struct{}
+
+func (c Set) UI() cli.UI {
+	return cli.UI{
+		RunCLI: c.runCLI,
+	}
+}
+
+func (c *Set) runCLI(ctx context.Context, streams cli.Streams) error {
+	cfg := cli.ConfigFromContext(ctx)
+
+	field, err := writableFieldFromArgs(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	value := cli.ArgsFromContext(ctx)[1]
+
+	fileCfg, err := cfg.ReadTOML()
+	if err != nil {
+		return err
+	}
+
+	if err := fileCfg.SetFieldString(field, value); err != nil {
+		if errors.Is(err, cli.ErrConfigFieldType) {
+			return cli.UserError{Err: err}
+		}
+		return err
+	}
+	if problems := checkEnum(field.Key, cli.ConfigEnums[field.Key], fileCfg.FieldValue(field)); len(problems) > 0 {
+		return cli.UserError{Err: errors.New(strings.Join(problems, "\n"))}
+	}
+
+	return fileCfg.WriteTOML()
+}
+
+type Unset struct{}
+
+func (c Unset) UI() cli.UI {
+	return cli.UI{
+		RunCLI: c.runCLI,
+	}
+}
+
+func (c *Unset) runCLI(ctx context.Context, streams cli.Streams) error {
+	cfg := cli.ConfigFromContext(ctx)
+
+	field, err := writableFieldFromArgs(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	if _, err := cfg.SystemFS().Stat(cfg.File.Path); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	fileCfg, err := cfg.ReadTOML()
+	if err != nil {
+		return err
+	}
+	fileCfg.ResetField(field)
+
+	return fileCfg.WriteTOML()
+}
+
+func writableFieldFromArgs(ctx context.Context, cfg *cli.Config) (cli.ConfigField, error) {
+	field, err := fieldFromArgs(ctx)
+	if err != nil {
+		return field, err
+	}
+	if field.ReadOnly {
+		return field, cli.UserError{Err: fmt.Errorf("%s is read-only and cannot be stored in %s", field.Key, cfg.File.Path)}
+	}
+	if cfg.File.Skip {
+		return field, cli.UserError{Err: fmt.Errorf("cannot update %s while --skip-config is set", cfg.File.Path)}
+	}
+	return field, nil
+}
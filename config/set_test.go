@@ -0,0 +1,116 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/clitest"
+	"github.com/anchordotdev/cli/cmdtest"
+)
+
+func TestCmdConfigSet(t *testing.T) {
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdConfigSet, "config", "set", "--help")
+	})
+}
+
+func TestCmdConfigUnset(t *testing.T) {
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdConfigUnset, "config", "unset", "--help")
+	})
+}
+
+func TestSet(t *testing.T) {
+	setup := func(t *testing.T) (context.Context, *cli.Config) {
+		cfg := new(cli.Config)
+		cfg.Test.SystemFS = clitest.TestFS{
+			"anchor.toml": &fstest.MapFile{
+				Data: []byte(heredoc.Doc(`
+					[org]
+					apid = 'test-org'
+				`)),
+			},
+		}
+		if err := cfg.Load(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		return cli.ContextWithConfig(context.Background(), cfg), cfg
+	}
+
+	t.Run("set", func(t *testing.T) {
+		ctx, cfg := setup(t)
+		ctx = cli.ContextWithArgs(ctx, []string{"service.cert-style", "acme"})
+
+		cmd := Set{}
+		require.NoError(t, cmd.UI().RunCLI(ctx, cli.Streams{}))
+
+		want := heredoc.Doc(`
+			version = 1
+
+			[org]
+			apid = 'test-org'
+
+			[service]
+			cert-style = 'acme'
+		`)
+		require.Equal(t, want, string(cfg.Test.SystemFS.(clitest.TestFS)["anchor.toml"].Data))
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		ctx, cfg := setup(t)
+		ctx = cli.ContextWithArgs(ctx, []string{"org.apid"})
+
+		cmd := Unset{}
+		require.NoError(t, cmd.UI().RunCLI(ctx, cli.Streams{}))
+
+		require.Equal(t, "version = 1\n", string(cfg.Test.SystemFS.(clitest.TestFS)["anchor.toml"].Data))
+	})
+
+	tests := []struct {
+		name string
+
+		args []string
+	}{
+		{
+			name: "readonly",
+			args: []string{"api.api-token", "ap0_s3cr3t"},
+		},
+		{
+			name: "unknown-key",
+			args: []string{"org.slug", "test-org"},
+		},
+		{
+			name: "invalid-type",
+			args: []string{"trust.no-sudo", "maybe"},
+		},
+		{
+			name: "invalid-enum",
+			args: []string{"service.category", "cobol"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cfg := setup(t)
+			ctx = cli.ContextWithArgs(ctx, test.args)
+
+			cmd := Set{}
+			err := cmd.UI().RunCLI(ctx, cli.Streams{})
+
+			var uerr cli.UserError
+			require.True(t, errors.As(err, &uerr), "want user error, got %v", err)
+
+			want := heredoc.Doc(`
+				[org]
+				apid = 'test-org'
+			`)
+			require.Equal(t, want, string(cfg.Test.SystemFS.(clitest.TestFS)["anchor.toml"].Data))
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/toml"
+)
+
+var ErrInvalidConfig = errors.New("invalid config file")
+
+var CmdConfigShow = cli.NewCmd[Show](CmdConfig, "show", func(cmd *cobra.Command) {
+	cfg := cli.ConfigFromCmd(cmd)
+
+	cmd.Flags().StringVar(&cfg.Config.Show.Format, "format", cli.Defaults.Config.Show.Format, "Output format, either toml or json.")
+	cmd.Flags().BoolVar(&cfg.Config.Show.Validate, "validate", cli.Defaults.Config.Show.Validate, "Check the config file for unknown keys and invalid values.")
+})
+
+type Show struct{}
+
+func (c Show) UI() cli.UI {
+	return cli.UI{
+		RunCLI: c.runCLI,
+	}
+}
+
+func (c *Show) runCLI(ctx context.Context, streams cli.Streams) error {
+	cfg := cli.ConfigFromContext(ctx)
+
+	if cfg.Config.Show.Validate {
+		problems, err := Validate(cfg)
+		if err != nil {
+			return err
+		}
+		if len(problems) == 0 {
+			_, err := fmt.Fprintf(streams.Out, "%s is valid.\n", cfg.File.Path)
+			return err
+		}
+		for _, problem := range problems {
+			fmt.Fprintf(streams.Out, "%s: %s\n", cfg.File.Path, problem)
+		}
+		return cli.UserError{Err: fmt.Errorf("%w: found %d problem(s) in %s", ErrInvalidConfig, len(problems), cfg.File.Path)}
+	}
+
+	switch cfg.Config.Show.Format {
+	case "toml":
+		return writeShowTOML(streams.Out, cfg)
+	case "json":
+		enc := json.NewEncoder(streams.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(cfg.ShowValues())
+	default:
+		return cli.UserError{Err: fmt.Errorf("unknown format %q, expected toml or json", cfg.Config.Show.Format)}
+	}
+}
+
+func writeShowTOML(w io.Writer, cfg *cli.Config) error {
+	var tables []string
+	fieldsByTable := make(map[string][]cli.ConfigField)
+	for _, field := range cli.ConfigFields {
+		if field.CommandFlag() {
+			continue
+		}
+
+		table := ""
+		if i := strings.LastIndex(field.Key, "."); i >= 0 {
+			table = field.Key[:i]
+		}
+		if _, ok := fieldsByTable[table]; !ok {
+			tables = append(tables, table)
+		}
+		fieldsByTable[table] = append(fieldsByTable[table], field)
+	}
+
+	// top-level keys must come before any table
+	slices.SortStableFunc(tables, func(a, b string) int {
+		switch {
+		case a == "" && b != "":
+			return -1
+		case a != "" && b == "":
+			return 1
+		}
+		return 0
+	})
+
+	var buf bytes.Buffer
+	for i, table := range tables {
+		if table != "" {
+			if i > 0 {
+				buf.WriteString("\n")
+			}
+			fmt.Fprintf(&buf, "[%s]\n", table)
+		}
+
+		for _, field := range fieldsByTable[table] {
+			value, err := tomlValue(cfg.DisplayValue(field))
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(&buf, "%s = %s # %s\n", strings.TrimPrefix(field.Key, table+"."), value, cfg.FieldSource(field))
+		}
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func tomlValue(value any) (string, error) {
+	data, err := toml.Marshal(map[string]any{"v": value})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(strings.TrimPrefix(string(data), "v = ")), nil
+}
+
+// Validate checks the config file for unknown keys and invalid values, and
+// returns a description of each problem found.
+func Validate(cfg *cli.Config) ([]string, error) {
+	f, err := cfg.SystemFS().Open(cfg.File.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, cli.UserError{Err: fmt.Errorf("config file %s not found", cfg.File.Path)}
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var problems []string
+
+	decoded := *cli.Defaults
+	err = toml.NewDecoder(f).DisallowUnknownFields().Decode(&decoded)
+
+	var (
+		derr *toml.DecodeError
+		serr *toml.StrictMissingError
+	)
+	switch {
+	case errors.As(err, &serr):
+		for _, missing := range serr.Errors {
+			row, _ := missing.Position()
+			problems = append(problems, fmt.Sprintf("line %d: unknown key %q", row, strings.Join(missing.Key(), ".")))
+		}
+	case errors.As(err, &derr):
+		return append(problems, toml.DescribeError(err)), nil
+	case err != nil:
+		return nil, err
+	}
+
+	if decoded.Version != 0 && decoded.Version < cli.ConfigVersion {
+		problems = append(problems, fmt.Sprintf("version %d is outdated, run `anchor config migrate` to upgrade to version %d", decoded.Version, cli.ConfigVersion))
+	}
+
+	for _, field := range cli.ConfigFields {
+		value := decoded.FieldValue(field)
+		if reflect.DeepEqual(value, cli.Defaults.FieldValue(field)) {
+			continue
+		}
+		problems = append(problems, checkEnum(field.Key, cli.ConfigEnums[field.Key], value)...)
+	}
+
+	for i, svc := range decoded.Services {
+		key := fmt.Sprintf("services[%d]", i)
+		if svc.Name == "" {
+			problems = append(problems, fmt.Sprintf("missing name for %q", key))
+		}
+		problems = append(problems, checkEnum(key+".category", cli.ConfigEnums["service.category"], svc.Category)...)
+		problems = append(problems, checkEnum(key+".cert-style", cli.ConfigEnums["service.cert-style"], svc.CertStyle)...)
+	}
+
+	return problems, nil
+}
+
+func checkEnum(key string, allowed []string, value any) []string {
+	if allowed == nil {
+		return nil
+	}
+
+	var values []string
+	switch value := value.(type) {
+	case string:
+		values = []string{value}
+	case []string:
+		values = value
+	}
+
+	var problems []string
+	for _, v := range values {
+		if v != "" && !slices.Contains(allowed, v) {
+			problems = append(problems, fmt.Sprintf("invalid value %q for %q, expected one of: %s", v, key, strings.Join(allowed, ", ")))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/clitest"
+	"github.com/anchordotdev/cli/cmdtest"
+	_ "github.com/anchordotdev/cli/testflags"
+)
+
+func TestCmdConfigShow(t *testing.T) {
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdConfigShow, "config", "show", "--help")
+	})
+
+	t.Run("--format json", func(t *testing.T) {
+		cfg := cmdtest.TestCfg(t, CmdConfigShow, "--format", "json")
+		require.Equal(t, "json", cfg.Config.Show.Format)
+	})
+
+	t.Run("--validate", func(t *testing.T) {
+		cfg := cmdtest.TestCfg(t, CmdConfigShow, "--validate")
+		require.True(t, cfg.Config.Show.Validate)
+	})
+}
+
+func TestShow(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := new(cli.Config)
+	cfg.Test.SystemFS = clitest.TestFS{
+		"anchor.toml": &fstest.MapFile{
+			Data: []byte(heredoc.Doc(`
+				[org]
+				apid = 'test-org'
+			`)),
+		},
+	}
+	if err := cfg.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	cfg.API.Token = "ap0_s3cr3t"
+	ctx = cli.ContextWithConfig(ctx, cfg)
+
+	t.Run("toml", func(t *testing.T) {
+		cfg.Config.Show.Format = "toml"
+
+		var out bytes.Buffer
+		cmd := Show{}
+		require.NoError(t, cmd.UI().RunCLI(ctx, cli.Streams{Out: &out}))
+
+		require.Contains(t, out.String(), "apid = 'test-org' # anchor.toml\n")
+		require.Contains(t, out.String(), "api-token = '[redacted]' # flag\n")
+		require.Contains(t, out.String(), "url = 'https://api.anchor.dev/v0' # default\n")
+		require.NotContains(t, out.String(), "s3cr3t")
+		require.NotContains(t, out.String(), "[config.show]")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		cfg.Config.Show.Format = "json"
+
+		var out bytes.Buffer
+		cmd := Show{}
+		require.NoError(t, cmd.UI().RunCLI(ctx, cli.Streams{Out: &out}))
+
+		var values map[string]cli.ShowValue
+		require.NoError(t, json.Unmarshal(out.Bytes(), &values))
+
+		require.Equal(t, cli.ShowValue{Value: "test-org", Source: "anchor.toml"}, values["org.apid"])
+		require.Equal(t, cli.ShowValue{Value: "[redacted]", Source: "flag"}, values["api.api-token"])
+
+		for _, key := range []string{"config.show.format", "debug.bundle.file", "lcl-host.proxy.to"} {
+			require.NotContains(t, values, key)
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+
+		toml string
+
+		problems []string
+	}{
+		{
+			name: "valid",
+
+			toml: heredoc.Doc(`
+				[org]
+				apid = 'test-org'
+
+				[service]
+				category = 'ruby'
+				cert-style = 'acme'
+			`),
+		},
+		{
+			name: "unknown-keys",
+
+			toml: heredoc.Doc(`
+				[org]
+				apid = 'test-org'
+				slug = 'test-org'
+
+				[unknown]
+				key = 'value'
+			`),
+
+			problems: []string{
+				`line 3: unknown key "org.slug"`,
+				`line 5: unknown key "unknown"`,
+			},
+		},
+		{
+			name: "invalid-enum",
+
+			toml: heredoc.Doc(`
+				[service]
+				category = 'cobol'
+			`),
+
+			problems: []string{
+				`invalid value "cobol" for "service.category", expected one of: ` + "apache, caddy, custom, debian, diagnostic, django, flask, go, javascript, localhost, mongodb, mysql, nextjs, nginx, postgresql, python, rails, ruby, sinatra",
+			},
+		},
+		{
+			name: "invalid-services",
+
+			toml: heredoc.Doc(`
+				[[services]]
+				name = 'api'
+				category = 'go'
+
+				[[services]]
+				cert-style = 'ftp'
+			`),
+
+			problems: []string{
+				`missing name for "services[1]"`,
+				`invalid value "ftp" for "services[1].cert-style", expected one of: acme, anchor, automated, manual, mkcert`,
+			},
+		},
+		{
+			name: "invalid-type",
+
+			toml: heredoc.Doc(`
+				[org]
+				apid = 42
+			`),
+
+			problems: []string{
+				"line 2: cannot decode TOML integer into field of type string",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := new(cli.Config)
+			cfg.File.Path = "anchor.toml"
+			cfg.Test.SystemFS = clitest.TestFS{
+				"anchor.toml": &fstest.MapFile{Data: []byte(test.toml)},
+			}
+
+			problems, err := Validate(cfg)
+			require.NoError(t, err)
+			require.Equal(t, test.problems, problems)
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/fatih/structtag"
+
+	"github.com/anchordotdev/cli/anchorcli"
+	"github.com/anchordotdev/cli/detection"
+	"github.com/anchordotdev/cli/keyring/backend"
+	lclModels "github.com/anchordotdev/cli/lcl/models"
+	"github.com/anchordotdev/cli/truststore"
+)
+
+// ConfigField describes a single (leaf) value of Config, keyed by its dotted
+// TOML path. Fields without a toml name use the kebab-case of their Go name.
+type ConfigField struct {
+	Key string
+
+	Env      string
+	Flag     string
+	ReadOnly bool
+	Secret   bool
+
+	// UserOnly fields are only set by env or the user and system config files,
+	// and ignored in the project config.
+	UserOnly bool
+
+	Index []int
+	Type  reflect.Type
+}
+
+// CommandFlag reports whether field only holds the flag of a single command,
+// rather than config in effect for every command.
+func (f ConfigField) CommandFlag() bool {
+	return f.ReadOnly && f.Flag != ""
+}
+
+var ConfigFields = configFields(reflect.TypeOf(Config{}), nil, nil, false)
+
+// ConfigEnums lists the allowed values of config fields, by key.
+var ConfigEnums = map[string][]string{
+	"config.show.format": {"json", "toml"},
+	"keyring.backend":    backend.Names,
+	"output":             {"json", "text"},
+	"service.category":   configCategories(),
+	"service.cert-style": lclModels.CertStyles,
+	"service.env-output": {"display", "dotenv", "export"},
+	"trust.stores":       truststore.StoreNames,
+}
+
+func configCategories() []string {
+	keys := slices.Collect(maps.Keys(detection.DetectorsByFlag))
+	for _, category := range anchorcli.Categories {
+		keys = append(keys, category.Key)
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
+func ConfigFieldByKey(key string) (ConfigField, bool) {
+	for _, field := range ConfigFields {
+		if field.Key == key {
+			return field, true
+		}
+	}
+	return ConfigField{}, false
+}
+
+func configFields(typ reflect.Type, path []string, index []int, readonly bool) []ConfigField {
+	var fields []ConfigField
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if !sf.IsExported() || sf.Name == "Test" || sf.Name == "Via" || sf.Name == "Services" {
+			continue
+		}
+
+		tags, err := structtag.Parse(string(sf.Tag))
+		if err != nil {
+			panic(err)
+		}
+
+		key, fieldReadonly := kebabCase(sf.Name), readonly
+		if tag, _ := tags.Get("toml"); tag != nil {
+			if tag.Name != "" {
+				key = tag.Name
+			}
+			fieldReadonly = fieldReadonly || slices.Contains(tag.Options, "readonly")
+		}
+
+		fieldPath := append(slices.Clone(path), key)
+		fieldIndex := append(slices.Clone(index), i)
+
+		if sf.Type.Kind() == reflect.Struct && sf.Type.PkgPath() != "time" {
+			fields = append(fields, configFields(sf.Type, fieldPath, fieldIndex, fieldReadonly)...)
+			continue
+		}
+
+		field := ConfigField{
+			Key:      strings.Join(fieldPath, "."),
+			ReadOnly: fieldReadonly,
+			Index:    fieldIndex,
+			Type:     sf.Type,
+		}
+		if tag, _ := tags.Get("env"); tag != nil {
+			field.Env = tag.Name
+		}
+		if tag, _ := tags.Get("flag"); tag != nil {
+			field.Flag = tag.Name
+		}
+		if tag, _ := tags.Get("secret"); tag != nil {
+			field.Secret = tag.Name == "true"
+		}
+		if tag, _ := tags.Get("useronly"); tag != nil {
+			field.UserOnly = tag.Name == "true"
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// SecretValues returns the values of secret fields that are set.
+func (c *Config) SecretValues() []string {
+	var values []string
+	for _, field := range ConfigFields {
+		if value, ok := c.FieldValue(field).(string); ok && field.Secret && value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func (c *Config) FieldValue(field ConfigField) any {
+	return reflect.ValueOf(c).Elem().FieldByIndex(field.Index).Interface()
+}
+
+var ErrConfigFieldType = errors.New("invalid config value")
+
+// SetFieldString parses value according to the type of field and sets it. Lists
+// are comma separated.
+func (c *Config) SetFieldString(field ConfigField, value string) error {
+	v := reflect.ValueOf(c).Elem().FieldByIndex(field.Index)
+
+	if field.Type == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%w %q for %s, expected a duration like 30s or 2m", ErrConfigFieldType, value, field.Key)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Type.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%w %q for %s, expected true or false", ErrConfigFieldType, value, field.Key)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, field.Type.Bits())
+		if err != nil {
+			return fmt.Errorf("%w %q for %s, expected an integer", ErrConfigFieldType, value, field.Key)
+		}
+		v.SetInt(i)
+	case reflect.Slice:
+		if field.Type.Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s for %s", field.Type, field.Key)
+		}
+
+		var values []string
+		for _, s := range strings.Split(value, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				values = append(values, s)
+			}
+		}
+		v.Set(reflect.ValueOf(values))
+	default:
+		return fmt.Errorf("unsupported type %s for %s", field.Type, field.Key)
+	}
+	return nil
+}
+
+// ResetField sets field back to its default value.
+func (c *Config) ResetField(field ConfigField) {
+	def := reflect.ValueOf(Defaults.Copy()).Elem().FieldByIndex(field.Index)
+	reflect.ValueOf(c).Elem().FieldByIndex(field.Index).Set(def)
+}
+
+// FieldSource is ViaSource for field.
+func (c *Config) FieldSource(field ConfigField) string {
+	return c.ViaSource(func(cfg *Config) any { return cfg.FieldValue(field) })
+}
+
+const redacted = "[redacted]"
+
+// ShowValue is the value of a config key for display, and its source.
+type ShowValue struct {
+	Value  any    `json:"value"`
+	Source string `json:"source"`
+}
+
+// ShowValues returns the value and source of every config key, with secrets
+// redacted. The flags of single commands are skipped.
+func (c *Config) ShowValues() map[string]ShowValue {
+	values := make(map[string]ShowValue, len(ConfigFields))
+	for _, field := range ConfigFields {
+		if field.CommandFlag() {
+			continue
+		}
+		values[field.Key] = ShowValue{
+			Value:  c.DisplayValue(field),
+			Source: c.FieldSource(field),
+		}
+	}
+	return values
+}
+
+// DisplayValue returns the value of field for display, with secrets redacted
+// and durations formatted.
+func (c *Config) DisplayValue(field ConfigField) any {
+	value := c.FieldValue(field)
+	if field.Secret && !reflect.ValueOf(value).IsZero() {
+		return redacted
+	}
+	if d, ok := value.(time.Duration); ok {
+		return d.String()
+	}
+	return value
+}
+
+func kebabCase(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('-')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mohae/deepcopy"
+
+	"github.com/anchordotdev/cli/toml"
+)
+
+// ConfigVersion is the version of the config file layout written by this CLI.
+// Files without a version are version 1.
+var ConfigVersion = LatestConfigVersion(ConfigMigrations)
+
+var ErrConfigVersion = errors.New("unsupported config file version")
+
+// ConfigMigration upgrades a decoded config file from the previous version to
+// Version.
+type ConfigMigration struct {
+	Version     int
+	Description string
+
+	Migrate func(doc map[string]any)
+}
+
+// ConfigMigrations are applied in order to config files written with an older
+// version, append new migrations when renaming or moving keys.
+var ConfigMigrations = []ConfigMigration{}
+
+// Migrations returns the config migrations in effect, ConfigMigrations unless
+// changed for testing.
+func (c *Config) Migrations() []ConfigMigration {
+	if c.Test.ConfigMigrations != nil {
+		return c.Test.ConfigMigrations
+	}
+	return ConfigMigrations
+}
+
+// LatestConfigVersion is the version config files are upgraded to by
+// migrations.
+func LatestConfigVersion(migrations []ConfigMigration) int {
+	return 1 + len(migrations)
+}
+
+// MigrateTOML upgrades the config file data to the latest version of
+// migrations, editing it in place, and returns it along with the version it was
+// written with (0 if unversioned). Unversioned files are stamped with the
+// latest version, even when no migration applies. Files from a newer version
+// fail with ErrConfigVersion.
+func MigrateTOML(data []byte, migrations []ConfigMigration) ([]byte, int, error) {
+	var doc map[string]any
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return nil, 0, err
+	}
+	if doc == nil {
+		doc = make(map[string]any)
+	}
+
+	version, err := configVersion(doc)
+	if err != nil {
+		return nil, 0, err
+	}
+	latest := LatestConfigVersion(migrations)
+	if version > latest {
+		return nil, version, fmt.Errorf("%w: %d", ErrConfigVersion, version)
+	}
+	if version == latest {
+		return data, version, nil
+	}
+
+	old := deepcopy.Copy(doc).(map[string]any)
+	for _, migration := range PendingConfigMigrations(migrations, version) {
+		migration.Migrate(doc)
+	}
+	doc["version"] = int64(latest)
+
+	migrated, err := toml.Edit(data, old, doc)
+	if err != nil {
+		return nil, 0, err
+	}
+	return migrated, version, nil
+}
+
+// PendingConfigMigrations returns the migrations needed to upgrade a config
+// file written with version.
+func PendingConfigMigrations(migrations []ConfigMigration, version int) []ConfigMigration {
+	var pending []ConfigMigration
+	for _, migration := range migrations {
+		if migration.Version > max(version, 1) {
+			pending = append(pending, migration)
+		}
+	}
+	return pending
+}
+
+func configVersion(doc map[string]any) (int, error) {
+	switch version := doc["version"].(type) {
+	case nil:
+		return 0, nil
+	case int64:
+		if version < 1 {
+			return 0, fmt.Errorf("invalid config file version %d", version)
+		}
+		return int(version), nil
+	default:
+		return 0, fmt.Errorf("invalid config file version %v", version)
+	}
+}
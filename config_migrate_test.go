@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+)
+
+// testConfigMigrations have a version 2 migration renaming the test keys
+// service.lang and service.style.
+var testConfigMigrations = []ConfigMigration{
+	{
+		Version:     2,
+		Description: "rename service.lang to service.category and service.style to service.cert-style",
+
+		Migrate: func(doc map[string]any) {
+			service, ok := doc["service"].(map[string]any)
+			if !ok {
+				return
+			}
+			for from, to := range map[string]string{"lang": "category", "style": "cert-style"} {
+				if value, ok := service[from]; ok {
+					service[to] = value
+					delete(service, from)
+				}
+			}
+		},
+	},
+}
+
+func TestMigrateTOML(t *testing.T) {
+	tests := []struct {
+		name string
+
+		toml string
+
+		want    string
+		version int
+	}{
+		{
+			name: "unversioned",
+
+			toml: heredoc.Doc(`
+				[org]
+				apid = "test-org"
+
+				[service]
+				# detected by lcl setup
+				lang = "ruby"
+				style = "acme" # recommended
+			`),
+
+			want: heredoc.Doc(`
+				version = 2
+
+				[org]
+				apid = "test-org"
+
+				[service]
+				# detected by lcl setup
+				category = 'ruby'
+				cert-style = 'acme'
+			`),
+		},
+		{
+			name: "current",
+
+			toml: heredoc.Doc(`
+				version = 2
+
+				[service]
+				category = "ruby"
+			`),
+
+			want: heredoc.Doc(`
+				version = 2
+
+				[service]
+				category = "ruby"
+			`),
+			version: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, version, err := MigrateTOML([]byte(test.toml), testConfigMigrations)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want, got := test.want, string(got); want != got {
+				t.Errorf("want migrated toml:\n%s\ngot:\n%s", want, got)
+			}
+			if want, got := test.version, version; want != got {
+				t.Errorf("want version %d, got %d", want, got)
+			}
+		})
+	}
+
+	t.Run("unversioned without migrations", func(t *testing.T) {
+		got, version, err := MigrateTOML([]byte("[org]\napid = \"test-org\"\n"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, got := "version = 1\n\n[org]\napid = \"test-org\"\n", string(got); want != got {
+			t.Errorf("want migrated toml:\n%s\ngot:\n%s", want, got)
+		}
+		if want, got := 0, version; want != got {
+			t.Errorf("want version %d, got %d", want, got)
+		}
+	})
+
+	t.Run("newer", func(t *testing.T) {
+		if _, _, err := MigrateTOML([]byte("version = 99\n"), testConfigMigrations); !errors.Is(err, ErrConfigVersion) {
+			t.Fatalf("want config version error, got %v", err)
+		}
+	})
+}
+
+func TestConfigLoadTOMLMigrated(t *testing.T) {
+	t.Run("older", func(t *testing.T) {
+		fs := fstest.MapFS{
+			"anchor.toml": &fstest.MapFile{
+				Data: []byte(heredoc.Doc(`
+					[service]
+					lang = "ruby"
+				`)),
+			},
+		}
+
+		cfg := defaultConfig()
+		cfg.Test.ConfigMigrations = testConfigMigrations
+		if err := cfg.loadTOML(fs); err != nil {
+			t.Fatal(err)
+		}
+		if want, got := "ruby", cfg.Service.Category; want != got {
+			t.Errorf("want category %q, got %q", want, got)
+		}
+	})
+
+	t.Run("newer", func(t *testing.T) {
+		fs := fstest.MapFS{
+			"anchor.toml": &fstest.MapFile{
+				Data: []byte("version = 99\n"),
+			},
+		}
+
+		cfg := defaultConfig()
+		err := cfg.loadTOML(fs)
+
+		var uerr UserError
+		if !errors.As(err, &uerr) {
+			t.Fatalf("want user error, got %v", err)
+		}
+		if want, got := "config file anchor.toml was written by a newer version of the anchor CLI (config version 99, this CLI supports up to 1), please update", err.Error(); want != got {
+			t.Errorf("want error %q, got %q", want, got)
+		}
+	})
+}
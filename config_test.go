@@ -3,6 +3,9 @@ package cli
 import (
 	"bytes"
 	"context"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 	"testing/fstest"
 	"unicode"
@@ -37,6 +40,10 @@ func TestConfigLoadENV(t *testing.T) {
 				"ANCHOR_CLI_TRUSTSTORE_MOCK_MODE": "true",
 				"ANCHOR_CONFIG":                   "other-anchor.toml",
 				"ANCHOR_HOST":                     "https://anchor.example.com",
+				"ANCHOR_KEYRING_BACKEND":          "file",
+				"ANCHOR_KEYRING_FILE":             "/tmp/anchor-credentials",
+				"ANCHOR_KEYRING_HELPER":           "anchor-credential-helper",
+				"ANCHOR_KEYRING_PASSPHRASE":       "open sesame",
 				"ANCHOR_SKIP_CONFIG":              "true",
 				"API_TOKEN":                       "s3cr3t!",
 				"API_URL":                         "https://api.anchor.example.com/v0",
@@ -81,6 +88,10 @@ func TestConfigLoadENV(t *testing.T) {
 				cfg.Trust.Stores = []string{"mock"}
 				cfg.Trust.Clean.States = []string{"valid"}
 				cfg.Keyring.MockMode = true
+				cfg.Keyring.Backend = "file"
+				cfg.Keyring.File.Path = "/tmp/anchor-credentials"
+				cfg.Keyring.Helper = "anchor-credential-helper"
+				cfg.Keyring.File.Passphrase = "open sesame"
 			},
 		},
 	}
@@ -180,6 +191,240 @@ func TestConfigLoadTOML(t *testing.T) {
 	}
 }
 
+func TestConfigLoadTOMLDiscovery(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(root, "app", "web")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "anchor.toml"), []byte("[org]\napid = \"test-org\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("parent-directory", func(t *testing.T) {
+		t.Chdir(sub)
+
+		cfg := defaultConfig()
+		cfg.Test.SystemTOML = filepath.Join(root, "missing-system.toml")
+		cfg.Test.UserTOML = filepath.Join(root, "missing-user.toml")
+		if err := cfg.loadTOML(osFS{}); err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := filepath.Join("..", "..", "anchor.toml"), cfg.File.Path; want != got {
+			t.Errorf("want config path %q, got %q", want, got)
+		}
+		if want, got := "test-org", cfg.Org.APID; want != got {
+			t.Errorf("want org apid %q, got %q", want, got)
+		}
+	})
+
+	t.Run("outside-repository", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "nested")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(filepath.Dir(dir), "anchor.toml"), []byte("[org]\napid = \"other-org\"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		t.Chdir(dir)
+
+		cfg := defaultConfig()
+		cfg.Test.SystemTOML = filepath.Join(root, "missing-system.toml")
+		cfg.Test.UserTOML = filepath.Join(root, "missing-user.toml")
+		if err := cfg.loadTOML(osFS{}); err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := "anchor.toml", cfg.File.Path; want != got {
+			t.Errorf("want config path %q, got %q", want, got)
+		}
+		if cfg.Org.APID != "" {
+			t.Errorf("want no org apid, got %q", cfg.Org.APID)
+		}
+	})
+}
+
+func TestConfigLoadTOMLPrecedence(t *testing.T) {
+	fs := fstest.MapFS{
+		"etc/anchor/config.toml": &fstest.MapFile{
+			Data: []byte(heredoc.Doc(`
+				[org]
+				apid = "system-org"
+
+				[realm]
+				apid = "system-realm"
+
+				[service]
+				apid = "system-service"
+			`)),
+		},
+		"home/anchor/config.toml": &fstest.MapFile{
+			Data: []byte(heredoc.Doc(`
+				[realm]
+				apid = "user-realm"
+
+				[service]
+				apid = "user-service"
+
+				[keyring]
+				backend = "file"
+			`)),
+		},
+		"anchor.toml": &fstest.MapFile{
+			Data: []byte(heredoc.Doc(`
+				[service]
+				apid = "project-service"
+
+				[keyring]
+				backend = "helper"
+				helper = "project-helper"
+
+				[keyring.file]
+				path = "project-keyring"
+			`)),
+		},
+	}
+
+	cfg := defaultConfig()
+	cfg.Test.SystemTOML = "etc/anchor/config.toml"
+	cfg.Test.UserTOML = "home/anchor/config.toml"
+	if err := cfg.loadTOML(fs); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.loadENV(); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Via.Defaults = defaultConfig()
+
+	tests := []struct {
+		name string
+
+		fetcher func(*Config) any
+
+		value, source string
+	}{
+		{"org", func(c *Config) any { return c.Org.APID }, "system-org", "etc/anchor/config.toml"},
+		{"realm", func(c *Config) any { return c.Realm.APID }, "user-realm", "home/anchor/config.toml"},
+		{"service", func(c *Config) any { return c.Service.APID }, "project-service", "anchor.toml"},
+		{"api-url", func(c *Config) any { return c.API.URL }, Defaults.API.URL, "default"},
+		{"keyring-backend", func(c *Config) any { return c.Keyring.Backend }, "file", "home/anchor/config.toml"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if want, got := test.value, test.fetcher(cfg); want != got {
+				t.Errorf("want value %q, got %q", want, got)
+			}
+			if want, got := test.source, cfg.ViaSource(test.fetcher); want != got {
+				t.Errorf("want source %q, got %q", want, got)
+			}
+		})
+	}
+
+	t.Run("keyring-ignored-in-project", func(t *testing.T) {
+		if got := cfg.Keyring.Helper; got != "" {
+			t.Errorf("want project keyring helper ignored, got %q", got)
+		}
+		if got := cfg.Keyring.File.Path; got != "" {
+			t.Errorf("want project keyring file ignored, got %q", got)
+		}
+	})
+}
+
+func TestConfigWorkspace(t *testing.T) {
+	fs := fstest.MapFS{
+		"anchor.toml": &fstest.MapFile{
+			Data: []byte(heredoc.Doc(`
+				[org]
+				apid = "test-org"
+
+				[[services]]
+				name = "api"
+				apid = "api-service"
+				category = "go"
+				domains = ["api.lcl.host"]
+
+				[[services]]
+				name = "web"
+				category = "ruby"
+				cert-style = "acme"
+			`)),
+		},
+	}
+
+	tests := []struct {
+		name string
+
+		service string
+
+		cfgFn func(*Config)
+	}{
+		{
+			name: "no-selection",
+
+			cfgFn: func(cfg *Config) {},
+		},
+		{
+			name: "by-apid",
+
+			service: "api-service",
+
+			cfgFn: func(cfg *Config) {
+				cfg.Service.APID = "api-service"
+				cfg.Service.Name = "api"
+				cfg.Service.Category = "go"
+				cfg.Service.Domains = []string{"api.lcl.host"}
+			},
+		},
+		{
+			name: "by-name",
+
+			service: "web",
+
+			cfgFn: func(cfg *Config) {
+				cfg.Service.Name = "web"
+				cfg.Service.Category = "ruby"
+				cfg.Service.CertStyle = "acme"
+			},
+		},
+		{
+			name: "not-in-workspace",
+
+			service: "admin",
+
+			cfgFn: func(cfg *Config) {
+				cfg.Service.APID = "admin"
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			if err := cfg.loadTOML(fs); err != nil {
+				t.Fatal(err)
+			}
+			if len(cfg.Services) != 2 {
+				t.Fatalf("want 2 workspace services, got %d", len(cfg.Services))
+			}
+
+			cfg.Service.APID = test.service
+			cfg.selectService()
+
+			expected := defaultConfig()
+			test.cfgFn(expected)
+
+			if diff := deep.Equal(expected.Service, cfg.Service); diff != nil {
+				t.Errorf("service does not match: %s", diff)
+			}
+		})
+	}
+}
+
 func TestConfigEncodeTOML(t *testing.T) {
 	tests := []struct {
 		name string
@@ -284,6 +529,7 @@ func TestRoundTrip(t *testing.T) {
 			if err := want.WriteTOML(); err != nil {
 				t.Fatal(err)
 			}
+			want.Version = ConfigVersion
 
 			var got Config
 			got.Test.SystemFS = want.Test.SystemFS
@@ -303,6 +549,142 @@ func TestRoundTrip(t *testing.T) {
 	}
 }
 
+func TestWriteTOMLEdit(t *testing.T) {
+	fs := clitest.TestFS{
+		"anchor.toml": &fstest.MapFile{
+			Data: []byte(heredoc.Doc(`
+				# shared lcl.host settings, see README
+				[org]
+				apid = "test-org" # team org
+
+				[tools]
+				editor = "vim"
+			`)),
+		},
+	}
+
+	cfg := new(Config)
+	cfg.Test.SystemFS = fs
+	if err := cfg.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.Service.APID = "test-service"
+	if err := cfg.WriteTOML(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the unversioned file is stamped with the current version
+	want := heredoc.Doc(`
+		# shared lcl.host settings, see README
+		version = 1
+
+		[org]
+		apid = "test-org" # team org
+
+		[tools]
+		editor = "vim"
+
+		[service]
+		apid = 'test-service'
+	`)
+	if got := string(fs["anchor.toml"].Data); want != got {
+		t.Errorf("want anchor.toml:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestWriteTOMLEditError(t *testing.T) {
+	data := []byte(heredoc.Doc(`
+		version = 1
+
+		# inline tables are not edited in place
+		org = { apid = "test-org" }
+	`))
+
+	fs := clitest.TestFS{
+		"anchor.toml": &fstest.MapFile{Data: data},
+	}
+
+	cfg := new(Config)
+	cfg.Test.SystemFS = fs
+	if err := cfg.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.Org.APID = "new-org"
+	if err := cfg.WriteTOML(); err == nil {
+		t.Fatal("want edit error, got nil")
+	}
+
+	if want, got := string(data), string(fs["anchor.toml"].Data); want != got {
+		t.Errorf("want anchor.toml unchanged:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestSetUserTOML(t *testing.T) {
+	t.Run("edit", func(t *testing.T) {
+		fs := clitest.TestFS{
+			"home/anchor/config.toml": &fstest.MapFile{
+				Data: []byte(heredoc.Doc(`
+					# personal defaults
+					[org]
+					apid = "test-org"
+				`)),
+			},
+		}
+
+		cfg := new(Config)
+		cfg.Test.SystemFS = fs
+		cfg.Test.UserTOML = "home/anchor/config.toml"
+
+		if err := cfg.SetUserTOML(map[string]any{"keyring.backend": "file"}); err != nil {
+			t.Fatal(err)
+		}
+
+		want := heredoc.Doc(`
+			# personal defaults
+			[org]
+			apid = "test-org"
+
+			[keyring]
+			backend = 'file'
+		`)
+		if got := string(fs["home/anchor/config.toml"].Data); want != got {
+			t.Errorf("want user config:\n%s\ngot:\n%s", want, got)
+		}
+
+		loaded := new(Config)
+		loaded.Test.SystemFS = fs
+		loaded.Test.UserTOML = "home/anchor/config.toml"
+		if err := loaded.Load(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if want, got := "file", loaded.Keyring.Backend; want != got {
+			t.Errorf("want keyring backend %q, got %q", want, got)
+		}
+	})
+
+	t.Run("create", func(t *testing.T) {
+		fs := clitest.TestFS{}
+
+		cfg := new(Config)
+		cfg.Test.SystemFS = fs
+		cfg.Test.UserTOML = "home/anchor/config.toml"
+
+		values := map[string]any{
+			"keyring.backend":   "file",
+			"keyring.file.path": "credentials",
+		}
+		if err := cfg.SetUserTOML(values); err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := "[keyring]\nbackend = 'file'\n\n[keyring.file]\npath = 'credentials'\n", string(fs["home/anchor/config.toml"].Data); want != got {
+			t.Errorf("want user config:\n%s\ngot:\n%s", want, got)
+		}
+	})
+}
+
 func TestConfigFieldTags(t *testing.T) {
 	var cfg Config
 	if err := gofakeit.Struct(&cfg); err != nil {
@@ -334,3 +716,115 @@ func TestConfigFieldTags(t *testing.T) {
 
 	checkfn(m)
 }
+
+func TestConfigFields(t *testing.T) {
+	keys := make(map[string]bool)
+	for _, field := range ConfigFields {
+		if keys[field.Key] {
+			t.Errorf("duplicate config field key %q", field.Key)
+		}
+		keys[field.Key] = true
+	}
+
+	tests := []struct {
+		key string
+
+		readonly, secret bool
+	}{
+		{key: "api.url"},
+		{key: "api.api-token", readonly: true, secret: true},
+		{key: "lcl-host.lcl-host-url", readonly: true},
+		{key: "non-interactive", readonly: true},
+		{key: "service.verify.timeout", readonly: true},
+		{key: "trust.stores", readonly: true},
+		{key: "keyring.backend", readonly: true},
+		{key: "keyring.file.path", readonly: true},
+	}
+
+	for _, test := range tests {
+		field, ok := ConfigFieldByKey(test.key)
+		if !ok {
+			t.Errorf("missing config field %q", test.key)
+			continue
+		}
+		if want, got := test.readonly, field.ReadOnly; want != got {
+			t.Errorf("want config field %q readonly %t, got %t", test.key, want, got)
+		}
+		if want, got := test.secret, field.Secret; want != got {
+			t.Errorf("want config field %q secret %t, got %t", test.key, want, got)
+		}
+	}
+}
+
+func TestWriteConfigFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes and symlinks differ on windows")
+	}
+
+	t.Run("keeps mode", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "anchor.toml")
+		if err := os.WriteFile(path, []byte("[org]\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := WriteConfigFile(osFS{}, path, []byte("[service]\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, got := os.FileMode(0600), fi.Mode().Perm(); want != got {
+			t.Errorf("want mode %v, got %v", want, got)
+		}
+	})
+
+	t.Run("follows symlink", func(t *testing.T) {
+		dir := t.TempDir()
+		target := filepath.Join(dir, "dotfiles-anchor.toml")
+		if err := os.WriteFile(target, []byte("[org]\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		link := filepath.Join(dir, "anchor.toml")
+		if err := os.Symlink(target, link); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := WriteConfigFile(osFS{}, link, []byte("[service]\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		fi, err := os.Lstat(link)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode()&os.ModeSymlink == 0 {
+			t.Errorf("want %s kept as a symlink, got mode %v", link, fi.Mode())
+		}
+
+		data, err := os.ReadFile(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, got := "[service]\n", string(data); want != got {
+			t.Errorf("want symlink target %q, got %q", want, got)
+		}
+	})
+
+	t.Run("new file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "anchor.toml")
+
+		if err := WriteConfigFile(osFS{}, path, []byte("[org]\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, got := os.FileMode(0644), fi.Mode().Perm(); want != got {
+			t.Errorf("want mode %v, got %v", want, got)
+		}
+	})
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package cli
+
+import (
+	"io/fs"
+	"os"
+)
+
+// keepOwner is a no-op, files on windows are owned through ACLs.
+func keepOwner(f *os.File, fi fs.FileInfo) error { return nil }
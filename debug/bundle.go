@@ -0,0 +1,326 @@
+package debug
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/eventlog"
+	"github.com/anchordotdev/cli/keyring"
+	"github.com/anchordotdev/cli/trust"
+)
+
+var CmdDebugBundle = cli.NewCmd[Bundle](CmdDebug, "bundle", func(cmd *cobra.Command) {
+	cfg := cli.ConfigFromCmd(cmd)
+
+	cmd.Flags().StringVar(&cfg.Debug.Bundle.File, "file", cli.Defaults.Debug.Bundle.File, "Bundle file to write. (default \"anchor-debug-<timestamp>.tar.gz\")")
+})
+
+// DNSTimeout limits how long each DNS lookup of the bundle takes.
+var DNSTimeout = 5 * time.Second
+
+// dnsNames are resolved for the bundle: lcl.host, and a subdomain as used for
+// local development.
+var dnsNames = []string{"lcl.host", "anchor-debug.lcl.host"}
+
+type Bundle struct{}
+
+type BundleResult struct {
+	Path  string   `json:"path"`
+	Files []string `json:"files"`
+}
+
+type bundleFile struct {
+	Name string
+	Data []byte
+}
+
+func (c Bundle) UI() cli.UI {
+	return cli.UI{
+		RunCLI: c.runCLI,
+	}
+}
+
+func (c *Bundle) runCLI(ctx context.Context, streams cli.Streams) error {
+	cfg := cli.ConfigFromContext(ctx)
+
+	path := cfg.Debug.Bundle.File
+	if path == "" {
+		path = fmt.Sprintf("anchor-debug-%s.tar.gz", cfg.Timestamp().UTC().Format("20060102T150405Z"))
+	}
+
+	files, secrets, err := collect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := writeBundle(path, cfg.Timestamp(), files, secrets); err != nil {
+		return err
+	}
+
+	res := BundleResult{Path: path}
+	for _, file := range files {
+		res.Files = append(res.Files, file.Name)
+	}
+	cli.SetResult(ctx, res)
+
+	_, err = fmt.Fprintf(streams.Out, "Wrote debug bundle to %s, attach it to an issue at https://github.com/anchordotdev/cli/issues.\n", path)
+	return err
+}
+
+// collect gathers the files of a bundle, and the secrets to redact from them.
+// Problems gathering a file are recorded in it rather than returned, so that
+// a bundle can be written on a broken system.
+func collect(ctx context.Context, cfg *cli.Config) ([]bundleFile, []string, error) {
+	secrets := cfg.SecretValues()
+
+	var files []bundleFile
+	add := func(name string, v any) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		files = append(files, bundleFile{Name: name, Data: append(data, '\n')})
+		return nil
+	}
+
+	if err := add("version.json", versionInfo()); err != nil {
+		return nil, nil, err
+	}
+	if err := add("config.json", cfg.ShowValues()); err != nil {
+		return nil, nil, err
+	}
+
+	keyringInfo, token := keyringStatus(cfg)
+	secrets = append(secrets, token)
+	if err := add("keyring.json", keyringInfo); err != nil {
+		return nil, nil, err
+	}
+
+	if err := add("trust.json", trustInventory(ctx)); err != nil {
+		return nil, nil, err
+	}
+	if err := add("dns.json", resolveDNS(ctx, cfg)); err != nil {
+		return nil, nil, err
+	}
+
+	if path := cfg.LastCommandLogPath(); path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return nil, nil, fmt.Errorf("reading last command log: %w", err)
+		default:
+			files = append(files, bundleFile{Name: "last-command.log", Data: data})
+
+			if stack := lastStack(data); stack != "" {
+				files = append(files, bundleFile{Name: "stack.txt", Data: []byte(stack + "\n")})
+			}
+		}
+	}
+
+	return files, secrets, nil
+}
+
+type VersionInfo struct {
+	Version    string `json:"version"`
+	Commit     string `json:"commit"`
+	Date       string `json:"date"`
+	OS         string `json:"os"`
+	Arch       string `json:"arch"`
+	GoVersion  string `json:"go_version"`
+	Executable string `json:"executable,omitempty"`
+}
+
+func versionInfo() VersionInfo {
+	executable := cli.Executable
+	if executable == "" {
+		executable, _ = os.Executable()
+	}
+
+	return VersionInfo{
+		Version:    cli.Version.Version,
+		Commit:     cli.Version.Commit,
+		Date:       cli.Version.Date,
+		OS:         cli.Version.Os,
+		Arch:       cli.Version.Arch,
+		GoVersion:  runtime.Version(),
+		Executable: executable,
+	}
+}
+
+type KeyringStatus struct {
+	Backend string `json:"backend"`
+	Token   string `json:"token"` // present, missing or the error reading it
+}
+
+// keyringStatus reports whether the keyring holds an API token, and returns
+// the token for redaction.
+func keyringStatus(cfg *cli.Config) (KeyringStatus, string) {
+	status := KeyringStatus{Backend: cfg.Keyring.Backend}
+	if status.Backend == "" {
+		status.Backend = keyring.BackendSystem
+	}
+
+	kr := keyring.Keyring{Config: cfg}
+	token, err := kr.Get(keyring.APIToken)
+	switch {
+	case errors.Is(err, keyring.ErrNotFound):
+		status.Token = "missing"
+	case err != nil:
+		status.Token = err.Error()
+	default:
+		status.Token = "present"
+	}
+	return status, token
+}
+
+type TrustStoreInventory struct {
+	Store string         `json:"store"`
+	Error string         `json:"error,omitempty"`
+	CAs   []TrustStoreCA `json:"cas"`
+}
+
+type TrustStoreCA struct {
+	Subject string `json:"subject"`
+	SHA256  string `json:"sha256"`
+}
+
+// trustInventory lists the subjects and fingerprints of the CA certificates
+// in the configured trust stores.
+func trustInventory(ctx context.Context) any {
+	stores, err := trust.LoadStores(ctx, nil)
+	if err != nil {
+		return struct {
+			Error string `json:"error"`
+		}{err.Error()}
+	}
+
+	inventory := []TrustStoreInventory{}
+	for _, store := range stores {
+		inv := TrustStoreInventory{
+			Store: store.Description(),
+			CAs:   []TrustStoreCA{},
+		}
+
+		cas, err := store.ListCAs()
+		if err != nil {
+			inv.Error = err.Error()
+		}
+		for _, ca := range cas {
+			sum := sha256.Sum256(ca.Raw)
+			inv.CAs = append(inv.CAs, TrustStoreCA{
+				Subject: ca.Subject.String(),
+				SHA256:  hex.EncodeToString(sum[:]),
+			})
+		}
+		inventory = append(inventory, inv)
+	}
+	return inventory
+}
+
+type DNSResult struct {
+	Name  string   `json:"name"`
+	Addrs []string `json:"addrs"`
+	Error string   `json:"error,omitempty"`
+}
+
+func resolveDNS(ctx context.Context, cfg *cli.Config) []DNSResult {
+	resolver := cfg.Test.NetResolver
+	if resolver == nil {
+		resolver = new(net.Resolver)
+	}
+
+	var results []DNSResult
+	for _, name := range dnsNames {
+		ctx, cancel := context.WithTimeout(ctx, DNSTimeout)
+		addrs, err := resolver.LookupHost(ctx, name)
+		cancel()
+
+		res := DNSResult{Name: name, Addrs: addrs}
+		if res.Addrs == nil {
+			res.Addrs = []string{}
+		}
+		if err != nil {
+			res.Error = err.Error()
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// lastStack returns the stack trace of the error the last command exited with,
+// if any.
+func lastStack(log []byte) string {
+	var stack string
+
+	scanner := bufio.NewScanner(bytes.NewReader(log))
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var event struct {
+			Type  string `json:"type"`
+			Stack string `json:"stack"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &event); err == nil && event.Type == "exit" {
+			stack = event.Stack
+		}
+	}
+	return stack
+}
+
+// writeBundle writes files to a gzipped tarball at path, redacting secrets,
+// personal access tokens and the home directory.
+func writeBundle(path string, modTime time.Time, files []bundleFile, secrets []string) error {
+	home, _ := os.UserHomeDir()
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return cli.UserError{Err: fmt.Errorf("creating debug bundle: %w", err)}
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	for _, file := range files {
+		data := eventlog.Scrub(file.Data, secrets...)
+		if home != "" && home != "/" {
+			data = bytes.ReplaceAll(data, []byte(home), []byte("<home>"))
+		}
+
+		hdr := &tar.Header{
+			Name:    file.Name,
+			Mode:    0600,
+			Size:    int64(len(data)),
+			ModTime: modTime,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
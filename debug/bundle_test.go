@@ -0,0 +1,120 @@
+package debug
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/cmdtest"
+	"github.com/anchordotdev/cli/truststore"
+)
+
+func TestCmdDebugBundle(t *testing.T) {
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdDebugBundle, "debug", "bundle", "--help")
+	})
+
+	t.Run("--file bundle.tar.gz", func(t *testing.T) {
+		cfg := cmdtest.TestCfg(t, CmdDebugBundle, "--file", "bundle.tar.gz")
+		require.Equal(t, "bundle.tar.gz", cfg.Debug.Bundle.File)
+	})
+}
+
+func TestBundle(t *testing.T) {
+	truststore.ResetMockCAs()
+	t.Cleanup(truststore.ResetMockCAs)
+
+	pat := "ap0_" + strings.Repeat("x", 60)
+
+	cfg := cmdtest.Config(context.Background())
+	cfg.API.Token = "s3cr3t-token"
+	cfg.Debug.Bundle.File = filepath.Join(t.TempDir(), "bundle.tar.gz")
+	cfg.Keyring.MockMode = true
+	cfg.Trust.MockMode = true
+	cfg.Trust.Stores = []string{"mock"}
+	cfg.Test.CacheDir = t.TempDir()
+	cfg.Test.NetResolver = &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			return nil, errors.New("offline")
+		},
+	}
+
+	log := strings.Join([]string{
+		`{"time":"2024-01-02T15:04:05Z","type":"command","args":["s3cr3t-token"],"command":"anchor lcl"}`,
+		`{"time":"2024-01-02T15:04:05Z","type":"api","path":"/` + pat + `"}`,
+		`{"time":"2024-01-02T15:04:05Z","type":"exit","error":"boom","exit_code":1,"stack":"goroutine 1 [running]:\nmain.main()"}`,
+	}, "\n") + "\n"
+	require.NoError(t, os.WriteFile(cfg.LastCommandLogPath(), []byte(log), 0600))
+
+	ctx := cli.ContextWithConfig(context.Background(), cfg)
+
+	var out bytes.Buffer
+	err := Bundle{}.UI().RunCLI(ctx, cli.Streams{Out: &out})
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "Wrote debug bundle to "+cfg.Debug.Bundle.File)
+
+	files := readBundle(t, cfg.Debug.Bundle.File)
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	require.ElementsMatch(t, []string{
+		"version.json",
+		"config.json",
+		"keyring.json",
+		"trust.json",
+		"dns.json",
+		"last-command.log",
+		"stack.txt",
+	}, names)
+
+	for name, data := range files {
+		require.NotContains(t, data, "s3cr3t-token", name)
+		require.NotContains(t, data, pat, name)
+	}
+
+	require.Equal(t, "goroutine 1 [running]:\nmain.main()\n", files["stack.txt"])
+	require.Contains(t, files["trust.json"], `"store": "Mock"`)
+	require.Contains(t, files["dns.json"], "offline")
+	require.Contains(t, files["keyring.json"], `"token": "missing"`)
+	require.Contains(t, files["config.json"], `"source": "default"`)
+}
+
+func readBundle(t *testing.T, path string) map[string]string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	files := make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = string(data)
+	}
+	return files
+}
@@ -0,0 +1,14 @@
+// Package debug collects diagnostics for reporting problems with the CLI.
+package debug
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+)
+
+// CmdDebug runs are not recorded as the last command, which its subcommands
+// report on.
+var CmdDebug = cli.NewCmd[cli.ShowHelp](cli.CmdRoot, "debug", func(cmd *cobra.Command) {
+	cmd.Annotations = map[string]string{cli.SkipLastCommandLog: ""}
+})
@@ -0,0 +1,18 @@
+package debug
+
+import (
+	"testing"
+
+	"github.com/anchordotdev/cli/cmdtest"
+	_ "github.com/anchordotdev/cli/testflags"
+)
+
+func TestCmdDebug(t *testing.T) {
+	t.Run("debug", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdDebug, "debug")
+	})
+
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdDebug, "debug", "--help")
+	})
+}
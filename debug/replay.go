@@ -0,0 +1,48 @@
+package debug
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/ui"
+)
+
+var CmdDebugReplay = cli.NewCmd[Replay](CmdDebug, "replay", func(cmd *cobra.Command) {})
+
+type Replay struct{}
+
+func (c Replay) UI() cli.UI {
+	return cli.UI{
+		RunCLI: c.runCLI,
+	}
+}
+
+func (c *Replay) runCLI(ctx context.Context, streams cli.Streams) error {
+	path := cli.ArgsFromContext(ctx)[0]
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cli.UserError{Err: fmt.Errorf("recording %s not found", path)}
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, events, err := ui.ReadCast(f)
+	if err != nil {
+		return cli.UserError{Err: fmt.Errorf("reading recording %s: %w", path, err)}
+	}
+
+	if err := ui.Replay(ctx, streams.Out, events); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(streams.Out)
+	return err
+}
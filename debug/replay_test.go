@@ -0,0 +1,47 @@
+package debug
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/cmdtest"
+)
+
+func TestCmdDebugReplay(t *testing.T) {
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdDebugReplay, "debug", "replay", "--help")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		err := cmdtest.TestError(t, CmdDebugReplay)
+		require.ErrorContains(t, err, "accepts 1 arg(s), received 0")
+	})
+}
+
+func TestReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.cast")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version":2,"width":80,"height":24}
+[0,"o","one\r\n"]
+[0.01,"o","\u001b[1A\r\u001b[Jtwo\r\n"]
+`), 0600))
+
+	ctx := cli.ContextWithConfig(context.Background(), cmdtest.Config(context.Background()))
+	ctx = cli.ContextWithArgs(ctx, []string{path})
+
+	var out bytes.Buffer
+	require.NoError(t, Replay{}.UI().RunCLI(ctx, cli.Streams{Out: &out}))
+	require.Equal(t, "one\r\n\x1b[1A\r\x1b[Jtwo\r\n\n", out.String())
+
+	t.Run("not found", func(t *testing.T) {
+		ctx := cli.ContextWithArgs(ctx, []string{filepath.Join(t.TempDir(), "missing.cast")})
+
+		err := Replay{}.UI().RunCLI(ctx, cli.Streams{Out: &out})
+		require.ErrorContains(t, err, "missing.cast not found")
+	})
+}
@@ -3,19 +3,10 @@ package diagnostic
 import (
 	"context"
 	"crypto/tls"
-	"io"
-	"log"
-	"net"
 	"net/http"
 	"sync"
-	"time"
-
-	"github.com/inetaf/tcpproxy"
-	"golang.org/x/sync/errgroup"
 )
 
-var discardLogger = log.New(io.Discard, "", 0)
-
 type Server struct {
 	Addr string
 
@@ -23,11 +14,7 @@ type Server struct {
 
 	GetCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)
 
-	proxy  *tcpproxy.Proxy
-	server *http.Server
-
-	ln   net.Listener
-	errg errgroup.Group
+	sni SNIServer
 
 	rchanmu sync.Mutex
 	rchan   chan string
@@ -37,53 +24,19 @@ type Server struct {
 }
 
 func (s *Server) Start(ctx context.Context) error {
-	var err error
-	if s.ln, err = new(net.ListenConfig).Listen(ctx, "tcp", s.Addr); err != nil {
-		return err
-	}
-	s.Addr = s.ln.Addr().String()
-
-	s.proxy = &tcpproxy.Proxy{
-		ListenFunc: func(string, string) (net.Listener, error) {
-			return s.ln, nil
-		},
-	}
-
-	lnTLS := &tcpproxy.TargetListener{
-		Address: s.Addr,
-	}
-
-	s.proxy.AddSNIRouteFunc(s.Addr, func(context.Context, string) (tcpproxy.Target, bool) {
-		return lnTLS, true
-	})
+	s.tlsc = make(chan struct{})
 
-	lnHTTP := &tcpproxy.TargetListener{
-		Address: s.Addr,
+	s.sni = SNIServer{
+		Addr:           s.Addr,
+		Handler:        http.HandlerFunc(s.serveHTTP),
+		GetCertificate: s.getCertificate,
 	}
-
-	s.proxy.AddRoute(s.Addr, lnHTTP)
-
-	s.server = &http.Server{
-		Addr: s.Addr,
-
-		Handler: http.HandlerFunc(s.serveHTTP),
-
-		BaseContext: func(net.Listener) context.Context { return ctx },
-		ErrorLog:    discardLogger,
+	if err := s.sni.Start(ctx); err != nil {
+		return err
 	}
+	s.Addr = s.sni.Addr
 
-	s.errg.Go(func() error {
-		return s.server.Serve(lnHTTP)
-	})
-	s.errg.Go(func() error {
-		return s.server.Serve(tls.NewListener(lnTLS, &tls.Config{
-			NextProtos:     []string{"h2", "http/1.1"},
-			GetCertificate: s.getCertificate,
-		}))
-	})
-
-	s.tlsc = make(chan struct{})
-	return s.proxy.Start()
+	return nil
 }
 
 func (s *Server) EnableTLS() {
@@ -91,29 +44,7 @@ func (s *Server) EnableTLS() {
 }
 
 func (s *Server) Close() error {
-	if err := s.ln.Close(); err != nil {
-		return err
-	}
-
-	if err := s.proxy.Close(); err != nil {
-		return err
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
-	defer cancel()
-
-	if err := s.server.Shutdown(ctx); err != nil {
-		return err
-	}
-	if err := s.server.Close(); err != nil {
-		return err
-	}
-
-	if err := s.errg.Wait(); err != nil {
-		return err
-	}
-
-	return s.proxy.Wait()
+	return s.sni.Close()
 }
 
 func (s *Server) getCertificate(chi *tls.ClientHelloInfo) (*tls.Certificate, error) {
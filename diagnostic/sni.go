@@ -0,0 +1,110 @@
+package diagnostic
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/inetaf/tcpproxy"
+	"golang.org/x/sync/errgroup"
+)
+
+var discardLogger = log.New(io.Discard, "", 0)
+
+// SNIServer serves Handler over plain HTTP and HTTPS on the same address. TLS
+// connections are told apart by their ClientHello, and terminated with
+// GetCertificate.
+type SNIServer struct {
+	Addr string
+
+	Handler http.Handler
+
+	GetCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)
+
+	proxy  *tcpproxy.Proxy
+	server *http.Server
+
+	ln   net.Listener
+	errg errgroup.Group
+}
+
+func (s *SNIServer) Start(ctx context.Context) error {
+	var err error
+	if s.ln, err = new(net.ListenConfig).Listen(ctx, "tcp", s.Addr); err != nil {
+		return err
+	}
+	s.Addr = s.ln.Addr().String()
+
+	s.proxy = &tcpproxy.Proxy{
+		ListenFunc: func(string, string) (net.Listener, error) {
+			return s.ln, nil
+		},
+	}
+
+	lnTLS := &tcpproxy.TargetListener{
+		Address: s.Addr,
+	}
+
+	s.proxy.AddSNIRouteFunc(s.Addr, func(context.Context, string) (tcpproxy.Target, bool) {
+		return lnTLS, true
+	})
+
+	lnHTTP := &tcpproxy.TargetListener{
+		Address: s.Addr,
+	}
+
+	s.proxy.AddRoute(s.Addr, lnHTTP)
+
+	s.server = &http.Server{
+		Addr: s.Addr,
+
+		Handler: s.Handler,
+
+		BaseContext: func(net.Listener) context.Context { return ctx },
+		ErrorLog:    discardLogger,
+	}
+
+	s.errg.Go(func() error {
+		return s.server.Serve(lnHTTP)
+	})
+	s.errg.Go(func() error {
+		return s.server.Serve(tls.NewListener(lnTLS, &tls.Config{
+			NextProtos:     []string{"h2", "http/1.1"},
+			GetCertificate: s.GetCertificate,
+		}))
+	})
+
+	return s.proxy.Start()
+}
+
+func (s *SNIServer) Close() error {
+	if err := s.ln.Close(); err != nil {
+		return err
+	}
+
+	if err := s.proxy.Close(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// open connections, such as websockets, are closed below
+	if err := s.server.Shutdown(ctx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	if err := s.server.Close(); err != nil {
+		return err
+	}
+
+	if err := s.errg.Wait(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return s.proxy.Wait()
+}
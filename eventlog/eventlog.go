@@ -0,0 +1,133 @@
+// Package eventlog writes a newline delimited JSON log of what a command does,
+// for debugging runs in CI.
+package eventlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+)
+
+const redacted = "[redacted]"
+
+// rePAT matches personal access tokens, which are redacted even when they are
+// not part of the config.
+var rePAT = regexp.MustCompile(`ap0_[A-Za-z0-9]{60}`)
+
+type Fields map[string]any
+
+// Log is an event log. A nil *Log discards events.
+type Log struct {
+	Now func() time.Time
+
+	mu      sync.Mutex
+	w       io.Writer
+	secrets [][]byte
+}
+
+// Open appends to the log file at path, creating it if needed.
+func Open(path string) (*Log, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return New(f), nil
+}
+
+func New(w io.Writer) *Log {
+	return &Log{
+		Now: time.Now,
+		w:   w,
+	}
+}
+
+// Redact replaces secrets in every later event.
+func (l *Log) Redact(secrets ...string) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, secret := range secrets {
+		if secret != "" {
+			l.secrets = append(l.secrets, []byte(secret))
+		}
+	}
+}
+
+// Event writes an event of typ with fields, along with a timestamp.
+func (l *Log) Event(typ string, fields Fields) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// time and type lead every line, followed by the fields in key order
+	header, _ := json.Marshal(struct {
+		Time string `json:"time"`
+		Type string `json:"type"`
+	}{
+		Time: l.Now().UTC().Format(time.RFC3339Nano),
+		Type: typ,
+	})
+
+	line := header
+	if len(fields) > 0 {
+		body, err := json.Marshal(fields)
+		if err != nil {
+			body, _ = json.Marshal(Fields{"error": err.Error()})
+		}
+
+		line = append(line[:len(line)-1], ',')
+		line = append(line, body[1:]...)
+	}
+
+	for _, secret := range l.secrets {
+		line = bytes.ReplaceAll(line, secret, []byte(redacted))
+	}
+	line = rePAT.ReplaceAll(line, []byte(redacted))
+
+	_, _ = l.w.Write(append(line, '\n'))
+}
+
+// Scrub replaces secrets and personal access tokens in data, such as a log
+// written with other secrets.
+func Scrub(data []byte, secrets ...string) []byte {
+	for _, secret := range secrets {
+		if secret != "" {
+			data = bytes.ReplaceAll(data, []byte(secret), []byte(redacted))
+		}
+	}
+	return rePAT.ReplaceAll(data, []byte(redacted))
+}
+
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+	if c, ok := l.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, l *Log) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the log of ctx, or nil.
+func FromContext(ctx context.Context) *Log {
+	l, _ := ctx.Value(contextKey{}).(*Log)
+	return l
+}
@@ -0,0 +1,47 @@
+package eventlog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(&buf)
+	log.Now = func() time.Time { return time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC) }
+
+	pat := "ap0_" + strings.Repeat("x", 60)
+
+	log.Redact("s3cr3t")
+	log.Event("command", Fields{"command": "anchor lcl", "args": []string{"--api-token", "s3cr3t"}})
+	log.Event("message", Fields{"message": "models.TokenMsg", "data": pat})
+	log.Event("stop", nil)
+
+	require.Equal(t, strings.Join([]string{
+		`{"time":"2024-01-02T15:04:05Z","type":"command","args":["--api-token","[redacted]"],"command":"anchor lcl"}`,
+		`{"time":"2024-01-02T15:04:05Z","type":"message","data":"[redacted]","message":"models.TokenMsg"}`,
+		`{"time":"2024-01-02T15:04:05Z","type":"stop"}`,
+	}, "\n")+"\n", buf.String())
+}
+
+func TestNilLog(t *testing.T) {
+	log := FromContext(context.Background())
+	require.Nil(t, log)
+
+	// a nil log discards events
+	log.Redact("s3cr3t")
+	log.Event("command", Fields{"command": "anchor"})
+	require.NoError(t, log.Close())
+}
+
+func TestScrub(t *testing.T) {
+	pat := "ap0_" + strings.Repeat("x", 60)
+
+	got := Scrub([]byte(`{"token":"`+pat+`","passphrase":"s3cr3t"}`), "s3cr3t", "")
+	require.Equal(t, `{"token":"[redacted]","passphrase":"[redacted]"}`, string(got))
+}
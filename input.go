@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Input is a value a command prompts for, along with the flag, environment
+// variable or answers file key that provides it instead.
+type Input struct {
+	Name string `json:"name"` // what is prompted for, such as "service category"
+	Flag string `json:"flag,omitempty"`
+	Env  string `json:"env,omitempty"`
+	ID   string `json:"answer,omitempty"` // key in the answers file
+
+	// Default is used in non-interactive mode, unless an answers file is used.
+	Default string `json:"-"`
+}
+
+func (i Input) String() string {
+	var sources []string
+	if i.Flag != "" {
+		sources = append(sources, i.Flag)
+	}
+	if i.Env != "" {
+		sources = append(sources, i.Env)
+	}
+	if i.ID != "" {
+		sources = append(sources, i.ID+" in the answers file")
+	}
+
+	switch len(sources) {
+	case 0:
+		return i.Name
+	case 1:
+		return fmt.Sprintf("%s (%s)", i.Name, sources[0])
+	default:
+		last := len(sources) - 1
+		return fmt.Sprintf("%s (%s or %s)", i.Name, strings.Join(sources[:last], ", "), sources[last])
+	}
+}
+
+// MissingInputError is returned instead of prompting in non-interactive mode.
+// Inputs lists the prompt that would have blocked, followed by any prompts
+// known to be pending after it.
+type MissingInputError struct {
+	Inputs []Input
+}
+
+func (e MissingInputError) Error() string {
+	var b strings.Builder
+	b.WriteString("cannot prompt in non-interactive mode, provide:")
+	for _, input := range e.Inputs {
+		fmt.Fprintf(&b, "\n  - %s", input)
+	}
+	return b.String()
+}
+
+// Add appends inputs not already listed.
+func (e *MissingInputError) Add(inputs ...Input) {
+	for _, input := range inputs {
+		input.Default = "" // not part of the listing
+		if !slices.Contains(e.Inputs, input) {
+			e.Inputs = append(e.Inputs, input)
+		}
+	}
+}
+
+// Answer returns the answer to the prompt for input, instead of prompting.
+// Answers come from the answers file when one is used, where any unanswered
+// prompt is an error. Otherwise, in non-interactive mode, the default of input
+// is the answer, and a MissingInputError is returned for inputs without one.
+// When ok is false and err is nil, the caller prompts as usual.
+func Answer(ctx context.Context, input Input) (answer string, ok bool, err error) {
+	if answers := AnswersFromContext(ctx); answers != nil {
+		if answer, ok := answers[input.ID]; ok && input.ID != "" {
+			return answer, true, nil
+		}
+		return "", false, missingInput(input)
+	}
+
+	cfg := ConfigFromContext(ctx)
+	if cfg == nil || !cfg.NonInteractive {
+		return "", false, nil
+	}
+	if input.Default != "" {
+		return input.Default, true, nil
+	}
+	return "", false, missingInput(input)
+}
+
+func missingInput(input Input) MissingInputError {
+	var err MissingInputError
+	err.Add(input)
+	return err
+}
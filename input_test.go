@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnswer(t *testing.T) {
+	category := Input{Name: "service category", Flag: "--category", Env: "SERVICE_CATEGORY", ID: AnswerCategory}
+	name := Input{Name: "service name", Env: "SERVICE_NAME", ID: AnswerServiceName, Default: "default-name"}
+
+	t.Run("interactive", func(t *testing.T) {
+		ctx := ContextWithConfig(context.Background(), new(Config))
+
+		_, ok, err := Answer(ctx, category)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("non-interactive", func(t *testing.T) {
+		cfg := new(Config)
+		cfg.NonInteractive = true
+		ctx := ContextWithConfig(context.Background(), cfg)
+
+		answer, ok, err := Answer(ctx, name)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "default-name", answer)
+
+		var merr MissingInputError
+		_, _, err = Answer(ctx, category)
+		require.True(t, errors.As(err, &merr))
+
+		merr.Add(category, Input{Name: "certificate style", Flag: "--cert-style", Env: "CERT_STYLE"}, name)
+
+		want := heredoc.Doc(`
+			cannot prompt in non-interactive mode, provide:
+			  - service category (--category, SERVICE_CATEGORY or category in the answers file)
+			  - certificate style (--cert-style or CERT_STYLE)
+			  - service name (SERVICE_NAME or service-name in the answers file)
+		`)
+		require.Equal(t, want[:len(want)-1], merr.Error())
+	})
+
+	t.Run("answers", func(t *testing.T) {
+		ctx := ContextWithAnswers(context.Background(), Answers{AnswerCategory: "go"})
+
+		answer, ok, err := Answer(ctx, category)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "go", answer)
+
+		// defaults don't answer prompts missing from the answers file
+		_, _, err = Answer(ctx, name)
+		require.True(t, errors.As(err, new(MissingInputError)))
+	})
+}
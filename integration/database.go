@@ -0,0 +1,167 @@
+package integration
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// Databases are the database servers DatabaseConfig generates TLS setup for.
+var Databases = []string{"mongodb", "mysql", "postgresql"}
+
+// IsDatabase reports whether the service category is a database server. These
+// take certificate files, instead of provisioning their own with ACME.
+func IsDatabase(category string) bool {
+	return slices.Contains(Databases, category)
+}
+
+// Credentials are the PEM encoded certificate chain and key of a service, and
+// the CA bundle of its realm.
+type Credentials struct {
+	Chain, Key, CAs []byte
+}
+
+// DatabaseTLS is the TLS setup of a database server.
+type DatabaseTLS struct {
+	Name string // such as "PostgreSQL"
+
+	// User is the system user the server conventionally runs as, which must be
+	// able to read the key file.
+	User string
+
+	// Files are the certificate files, with the permissions the server demands
+	// of them.
+	Files []File
+
+	// KeyFile is the file with the private key, which User must own.
+	KeyFile string
+
+	// Config are the lines enabling TLS for ConfigFile, the server config.
+	ConfigFile string
+	Config     string
+
+	// Connection is a client connection string verifying the server against the
+	// CA bundle.
+	Connection string
+}
+
+// queryEscaper escapes a path in a connection string query, leaving slashes
+// alone so it stays readable.
+var queryEscaper = strings.NewReplacer("%", "%25", " ", "%20", "&", "%26", "#", "%23", "+", "%2B")
+
+var databaseFuncs = template.FuncMap{"query": queryEscaper.Replace}
+
+type databaseTemplate struct {
+	name, user, configFile string
+
+	// combined is set for servers that take the certificate chain and key as
+	// a single file.
+	combined bool
+
+	config, connection *template.Template
+}
+
+var databaseTemplates = map[string]databaseTemplate{
+	"mongodb": {
+		name:       "MongoDB",
+		user:       "mongodb",
+		configFile: "mongod.conf",
+		combined:   true,
+
+		config: template.Must(template.New("mongodb").Parse(`net:
+  port: {{ .Port }}
+  tls:
+    mode: requireTLS
+    certificateKeyFile: {{ .KeyFile }}
+`)),
+		connection: template.Must(template.New("mongodb-connection").Funcs(databaseFuncs).Parse(
+			`mongodb://{{ .Host }}:{{ .Port }}/?tls=true&tlsCAFile={{ .CAFile | query }}`,
+		)),
+	},
+	"mysql": {
+		name:       "MySQL",
+		user:       "mysql",
+		configFile: "my.cnf",
+
+		config: template.Must(template.New("mysql").Parse(`[mysqld]
+port={{ .Port }}
+ssl-cert={{ .CertFile }}
+ssl-key={{ .KeyFile }}
+require_secure_transport=ON
+`)),
+		connection: template.Must(template.New("mysql-connection").Funcs(databaseFuncs).Parse(
+			`mysql://{{ .Host }}:{{ .Port }}?ssl-mode=VERIFY_IDENTITY&ssl-ca={{ .CAFile | query }}`,
+		)),
+	},
+	"postgresql": {
+		name:       "PostgreSQL",
+		user:       "postgres",
+		configFile: "postgresql.conf",
+
+		config: template.Must(template.New("postgresql").Parse(`port = {{ .Port }}
+ssl = on
+ssl_cert_file = '{{ .CertFile }}'
+ssl_key_file = '{{ .KeyFile }}'
+`)),
+		connection: template.Must(template.New("postgresql-connection").Funcs(databaseFuncs).Parse(
+			`postgresql://{{ .Host }}:{{ .Port }}/postgres?sslmode=verify-full&sslrootcert={{ .CAFile | query }}`,
+		)),
+	},
+}
+
+// DatabaseConfig returns the TLS setup of the database server for params and
+// creds. The certificate chain, key and CA bundle are written to the CertFile,
+// KeyFile and CAFile of params. Servers that take the chain and key as a single
+// file get both in the KeyFile.
+func DatabaseConfig(category string, params Params, creds Credentials) (*DatabaseTLS, error) {
+	dt, ok := databaseTemplates[category]
+	if !ok {
+		return nil, fmt.Errorf("unknown database %q, expected one of: %s", category, strings.Join(Databases, ", "))
+	}
+	if len(params.ServerNames) == 0 {
+		return nil, fmt.Errorf("no server names for %s config", dt.name)
+	}
+
+	// clients connect to a name, not a wildcard
+	host := params.ServerNames[0]
+	if idx := slices.IndexFunc(params.ServerNames, func(name string) bool { return !strings.HasPrefix(name, "*.") }); idx != -1 {
+		host = params.ServerNames[idx]
+	}
+
+	data := struct {
+		Params
+		Host string
+	}{params, host}
+
+	var config, connection bytes.Buffer
+	if err := dt.config.Execute(&config, data); err != nil {
+		return nil, err
+	}
+	if err := dt.connection.Execute(&connection, data); err != nil {
+		return nil, err
+	}
+
+	// servers refuse keys readable by others, such as PostgreSQL with "private
+	// key file has group or world access"
+	files := []File{
+		{Path: params.KeyFile, Mode: 0600, New: creds.Key},
+		{Path: params.CAFile, Mode: 0644, New: creds.CAs},
+	}
+	if dt.combined {
+		files[0].New = append(slices.Clone(creds.Chain), creds.Key...)
+	} else {
+		files = append([]File{{Path: params.CertFile, Mode: 0644, New: creds.Chain}}, files...)
+	}
+
+	return &DatabaseTLS{
+		Name:       dt.name,
+		User:       dt.user,
+		Files:      files,
+		KeyFile:    params.KeyFile,
+		ConfigFile: dt.configFile,
+		Config:     config.String(),
+		Connection: connection.String(),
+	}, nil
+}
@@ -0,0 +1,131 @@
+package integration
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+)
+
+const goAutocertFile = "anchor_tls.go"
+
+var goAutocertTmpl = template.Must(template.New(goAutocertFile).Parse(`// Code generated by ` + "`anchor lcl setup`" + `. You may edit it.
+
+package {{ .Package }}
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"net/http"
+	"os"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// anchorServerNames are the lcl.host domains of the service.
+var anchorServerNames = []string{ {{- range $i, $name := .ServerNames }}{{ if $i }}, {{ end }}{{ printf "%q" $name }}{{ end -}} }
+
+// anchorTLSConfig returns a TLS config with lcl.host certificates provisioned
+// and renewed by Anchor over ACME. It reads the ACME_* variables from
+// ` + "`anchor lcl env`" + `.
+func anchorTLSConfig() (*tls.Config, error) {
+	hmacKey, err := base64.RawURLEncoding.DecodeString(os.Getenv("ACME_HMAC_KEY"))
+	if err != nil {
+		return nil, err
+	}
+
+	mgr := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache("tmp/autocert"),
+		HostPolicy: autocert.HostWhitelist(anchorServerNames...),
+		Email:      os.Getenv("ACME_CONTACT"),
+
+		Client: &acme.Client{
+			DirectoryURL: os.Getenv("ACME_DIRECTORY_URL"),
+		},
+		ExternalAccountBinding: &acme.ExternalAccountBinding{
+			KID: os.Getenv("ACME_KID"),
+			Key: hmacKey,
+		},
+	}
+	return mgr.TLSConfig(), nil
+}
+
+// anchorListenAndServeTLS serves handler over HTTPS on the lcl.host port.
+func anchorListenAndServeTLS(handler http.Handler) error {
+	tlsConfig, err := anchorTLSConfig()
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Addr:      ":{{ .Port }}",
+		Handler:   handler,
+		TLSConfig: tlsConfig,
+	}
+	return srv.ListenAndServeTLS("", "")
+}
+`))
+
+func goAutocert(fsys FS, params Params) (*Integration, error) {
+	pkg, err := goPackage(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	err = goAutocertTmpl.Execute(&buf, struct {
+		Params
+		Package string
+	}{params, pkg})
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := create(fsys, goAutocertFile, 0644, buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Integration{
+		Name:  "Go autocert",
+		Files: files,
+		Steps: []string{
+			"Run `go get golang.org/x/crypto/acme/autocert` to add the autocert dependency.",
+			"Serve your handler with `anchorListenAndServeTLS(handler)` from " + goAutocertFile + ".",
+			"Add the ACME_* variables from `anchor lcl env` to your environment.",
+		},
+	}, nil
+}
+
+// goPackage returns the package name of the Go files at the root of fsys,
+// defaulting to main.
+func goPackage(fsys FS) (string, error) {
+	entries, err := fsys.ReadDir(".")
+	if err != nil {
+		return "", err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || path.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		src, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return "", err
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), name, src, parser.PackageClauseOnly)
+		if err != nil {
+			continue // not ours to fix
+		}
+		return file.Name.Name, nil
+	}
+	return "main", nil
+}
@@ -0,0 +1,198 @@
+package integration
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"slices"
+
+	"github.com/aymanbagabas/go-udiff"
+
+	"github.com/anchordotdev/cli/detection"
+)
+
+// FS is the project directory integrations are generated for and written to.
+type FS interface {
+	fs.ReadDirFS
+	fs.StatFS
+
+	WriteFile(name string, data []byte, perm os.FileMode) error
+}
+
+// Params are the values of the service an integration is generated for.
+type Params struct {
+	// ServerNames are the domains of the certificate, the lcl.host one first.
+	ServerNames []string
+	Port        int
+
+	// ACMEDirectoryURL is the ACME directory of the realm, for integrations
+	// that take it as config instead of from the environment.
+	ACMEDirectoryURL string
+
+	// CertFile and KeyFile are the PEM files of the provisioned certificate,
+	// for integrations that can't provision their own with ACME.
+	CertFile, KeyFile string
+
+	// CAFile is the PEM file of the realm's CA bundle, for clients verifying
+	// the server.
+	CAFile string
+}
+
+// Integration is the generated code and config for serving a project over
+// HTTPS with lcl.host certificates.
+type Integration struct {
+	Name  string // such as "Go autocert"
+	Files []File
+
+	// CertFiles is set when the integration uses the CertFile and KeyFile of
+	// its params, which are written along with the files.
+	CertFiles bool
+
+	// Steps are what is left to do after writing the files, such as installing
+	// dependencies.
+	Steps []string
+}
+
+// File is a file an integration creates or changes.
+type File struct {
+	Path string
+	Mode os.FileMode
+
+	Old []byte // nil when the file is created
+	New []byte
+}
+
+// Diff returns the change to the file as a unified diff.
+func (f File) Diff() string {
+	from := "a/" + f.Path
+	if f.Old == nil {
+		from = "/dev/null"
+	}
+	return udiff.Unified(from, "b/"+f.Path, string(f.Old), string(f.New))
+}
+
+// Diff returns the changes to all of the files as a unified diff.
+func (i *Integration) Diff() string {
+	var diff string
+	for _, file := range i.Files {
+		diff += file.Diff()
+	}
+	return diff
+}
+
+// Write writes the files to fsys.
+func (i *Integration) Write(fsys FS) error {
+	for _, file := range i.Files {
+		if err := fsys.WriteFile(file.Path, file.New, file.Mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Generator generates the integration for a project in fsys.
+type Generator func(fsys FS, params Params) (*Integration, error)
+
+type key struct {
+	category  string
+	framework string // a detector title, empty for any framework
+}
+
+var generators = map[key]Generator{
+	{"go", ""}:                goAutocert,
+	{"javascript", ""}:        nodeHTTPS,
+	{"javascript", "Next.js"}: nextHTTPS,
+	{"python", "Django"}:      djangoRunserver,
+	{"python", "Flask"}:       flaskRun,
+	{"ruby", "Ruby on Rails"}: railsPuma,
+
+	{"apache", ""}: serverGenerator("apache"),
+	{"caddy", ""}:  serverGenerator("caddy"),
+	{"nginx", ""}:  serverGenerator("nginx"),
+}
+
+// frameworks are the detectors for the frameworks with their own generator.
+var frameworks = []detection.Detector{
+	detection.NextJS,
+	detection.Django,
+	detection.Flask,
+	detection.Rails,
+}
+
+// Detect returns the generator for a project of the service category in fsys,
+// picking the framework with the most confident detection. It returns false
+// when there is none.
+func Detect(fsys FS, category string) (Generator, bool, error) {
+	framework, err := detectFramework(fsys, category)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if gen, ok := generators[key{category, framework}]; ok {
+		return gen, true, nil
+	}
+	gen, ok := generators[key{category, ""}]
+	return gen, ok, nil
+}
+
+func detectFramework(fsys FS, category string) (string, error) {
+	var best detection.Match
+	for _, detector := range frameworks {
+		match, err := detector.Detect(readFileFS{fsys})
+		if errors.Is(err, fs.ErrNotExist) {
+			continue // such as package.json for Next.js
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if !match.Detected || match.AnchorCategory == nil || match.AnchorCategory.Key != category {
+			continue
+		}
+		if match.Confidence > best.Confidence {
+			best = match
+		}
+	}
+
+	if best.Detector == nil {
+		return "", nil
+	}
+	return best.Detector.GetTitle(), nil
+}
+
+type readFileFS struct{ FS }
+
+func (f readFileFS) ReadFile(name string) ([]byte, error) { return fs.ReadFile(f.FS, name) }
+
+// change returns the file at path with contents from update, which is passed
+// the current contents, or nil when the file does not exist. It returns false
+// when the contents are unchanged.
+func change(fsys FS, path string, mode os.FileMode, update func(old []byte) []byte) (File, bool, error) {
+	old, err := fs.ReadFile(fsys, path)
+	if errors.Is(err, fs.ErrNotExist) {
+		old = nil
+	} else if err != nil {
+		return File{}, false, err
+	}
+
+	data := update(slices.Clone(old))
+	if old != nil && bytes.Equal(old, data) {
+		return File{}, false, nil
+	}
+
+	if info, err := fs.Stat(fsys, path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	return File{Path: path, Mode: mode, Old: old, New: data}, true, nil
+}
+
+// create returns the file at path with contents data, replacing the current
+// contents.
+func create(fsys FS, path string, mode os.FileMode, data []byte) ([]File, error) {
+	file, ok, err := change(fsys, path, mode, func([]byte) []byte { return data })
+	if err != nil || !ok {
+		return nil, err
+	}
+	return []File{file}, nil
+}
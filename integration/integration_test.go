@@ -0,0 +1,293 @@
+package integration
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/anchordotdev/cli/clitest"
+)
+
+var params = Params{
+	ServerNames: []string{"test-app.lcl.host", "test-app.localhost"},
+	Port:        4433,
+	CertFile:    "./test-app.lcl.host+1-chain.pem",
+	KeyFile:     "./test-app.lcl.host+1-key.pem",
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+
+		category string
+		fs       clitest.TestFS
+
+		integration string
+	}{
+		{
+			name: "go",
+
+			category: "go",
+			fs: clitest.TestFS{
+				"go.mod": {},
+			},
+
+			integration: "Go autocert",
+		},
+		{
+			name: "javascript",
+
+			category: "javascript",
+			fs: clitest.TestFS{
+				"package.json": {Data: []byte(`{"main": "server.js"}`)},
+			},
+
+			integration: "Node.js HTTPS",
+		},
+		{
+			name: "nextjs",
+
+			category: "javascript",
+			fs: clitest.TestFS{
+				"package.json": {Data: []byte(`{"dependencies": {"next": "14.0.0"}}`)},
+			},
+
+			integration: "Next.js HTTPS",
+		},
+		{
+			name: "django",
+
+			category: "python",
+			fs: clitest.TestFS{
+				"manage.py": {},
+			},
+
+			integration: "Django runserver_plus",
+		},
+		{
+			name: "flask",
+
+			category: "python",
+			fs: clitest.TestFS{
+				"app.py": {},
+			},
+
+			integration: "Flask run",
+		},
+		{
+			name: "python-without-framework",
+
+			category: "python",
+			fs: clitest.TestFS{
+				"requirements.txt": {},
+			},
+		},
+		{
+			name: "rails",
+
+			category: "ruby",
+			fs: clitest.TestFS{
+				"Gemfile":   {},
+				"config.ru": {},
+				"app":       {Mode: fs.ModeDir},
+				"config":    {Mode: fs.ModeDir},
+			},
+
+			integration: "Rails Puma",
+		},
+		{
+			name: "sinatra",
+
+			category: "ruby",
+			fs: clitest.TestFS{
+				"Gemfile": {},
+				"app.rb":  {},
+			},
+		},
+		{
+			name: "nginx",
+
+			category: "nginx",
+			fs:       clitest.TestFS{},
+
+			integration: "nginx",
+		},
+		{
+			name: "custom",
+
+			category: "custom",
+			fs:       clitest.TestFS{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gen, ok, err := Detect(test.fs, test.category)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := test.integration != ""; ok != want {
+				t.Fatalf("want integration %t, got %t", want, ok)
+			}
+			if !ok {
+				return
+			}
+
+			in, err := gen(test.fs, params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want, got := test.integration, in.Name; want != got {
+				t.Errorf("want integration %q, got %q", want, got)
+			}
+			if len(in.Files) == 0 {
+				t.Error("want integration files, got none")
+			}
+		})
+	}
+}
+
+func TestGoAutocert(t *testing.T) {
+	fsys := clitest.TestFS{
+		"server.go":      {Data: []byte("package server\n")},
+		"server_test.go": {Data: []byte("package server_test\n")},
+	}
+
+	in, err := goAutocert(fsys, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := string(in.Files[0].New)
+	for _, want := range []string{
+		"package server\n",
+		`var anchorServerNames = []string{"test-app.lcl.host", "test-app.localhost"}`,
+		`Addr:      ":4433",`,
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("want %s to contain %q, got:\n%s", goAutocertFile, want, data)
+		}
+	}
+
+	if err := in.Write(fsys); err != nil {
+		t.Fatal(err)
+	}
+
+	in, err = goAutocert(fsys, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(in.Files) > 0 {
+		t.Errorf("want no changes once written, got:\n%s", in.Diff())
+	}
+}
+
+func TestRailsPuma(t *testing.T) {
+	fsys := clitest.TestFS{
+		"config/puma.rb": {Data: []byte("port ENV.fetch(\"PORT\", 3000)"), Mode: 0600},
+	}
+
+	in, err := railsPuma(fsys, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := in.Files[0]
+	if want, got := fs.FileMode(0600), file.Mode; want != got {
+		t.Errorf("want mode %s, got %s", want, got)
+	}
+
+	data := string(file.New)
+	for _, want := range []string{
+		"port ENV.fetch(\"PORT\", 3000)\n\n# Added by `anchor lcl setup`",
+		`acme_server_name "test-app.lcl.host", "test-app.localhost"`,
+		`bind "acme://127.0.0.1:4433"`,
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("want %s to contain %q, got:\n%s", railsPumaFile, want, data)
+		}
+	}
+
+	diff := in.Diff()
+	if !strings.Contains(diff, "--- a/config/puma.rb\n+++ b/config/puma.rb\n") {
+		t.Errorf("want diff of %s, got:\n%s", railsPumaFile, diff)
+	}
+
+	if err := in.Write(fsys); err != nil {
+		t.Fatal(err)
+	}
+
+	in, err = railsPuma(fsys, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(in.Files) > 0 {
+		t.Errorf("want no changes once written, got:\n%s", in.Diff())
+	}
+}
+
+func TestFlaskRun(t *testing.T) {
+	fsys := clitest.TestFS{
+		".flaskenv": {Data: []byte("FLASK_APP=app.py\nFLASK_RUN_PORT=5000\n")},
+	}
+
+	in, err := flaskRun(fsys, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !in.CertFiles {
+		t.Error("want cert files for flask")
+	}
+
+	want := `FLASK_APP=app.py
+FLASK_RUN_PORT=4433
+FLASK_RUN_CERT=./test-app.lcl.host+1-chain.pem
+FLASK_RUN_KEY=./test-app.lcl.host+1-key.pem
+FLASK_RUN_HOST=127.0.0.1
+`
+	if got := string(in.Files[0].New); want != got {
+		t.Errorf("want %s:\n%s\ngot:\n%s", flaskEnvFile, want, got)
+	}
+}
+
+func TestDjangoRunserver(t *testing.T) {
+	in, err := djangoRunserver(clitest.TestFS{}, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := in.Files[0]
+	if want, got := fs.FileMode(0755), file.Mode; want != got {
+		t.Errorf("want mode %s, got %s", want, got)
+	}
+	if !strings.HasPrefix(file.Diff(), "--- /dev/null\n+++ b/anchor-runserver.sh\n") {
+		t.Errorf("want diff creating %s, got:\n%s", djangoRunserverFile, file.Diff())
+	}
+
+	want := "runserver_plus --cert-file ./test-app.lcl.host+1-chain.pem --key-file ./test-app.lcl.host+1-key.pem 127.0.0.1:4433"
+	if got := string(file.New); !strings.Contains(got, want) {
+		t.Errorf("want %s to contain %q, got:\n%s", djangoRunserverFile, want, got)
+	}
+}
+
+func TestNodeHTTPS(t *testing.T) {
+	fsys := clitest.TestFS{
+		"package.json": {Data: []byte(`{"main": "./src/server.js"}`)},
+	}
+
+	in, err := nodeHTTPS(fsys, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := string(in.Files[0].New)
+	for _, want := range []string{
+		`const handler = require("./src/server.js");`,
+		`const serverNames = ["test-app.lcl.host", "test-app.localhost"];`,
+		"const port = 4433;",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("want %s to contain %q, got:\n%s", nodeHTTPSFile, want, data)
+		}
+	}
+}
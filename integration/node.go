@@ -0,0 +1,159 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"path"
+	"text/template"
+)
+
+const nodeHTTPSFile = "anchor-https.js"
+
+var nodeHTTPSTmpl = template.Must(template.New(nodeHTTPSFile).Parse(`// Generated by ` + "`anchor lcl setup`" + `. You may edit it.
+//
+// Serves your application over HTTPS with lcl.host certificates provisioned
+// and renewed by Anchor over ACME. It reads the ACME_* variables from
+// ` + "`anchor lcl env`" + `. ACME challenges are answered over HTTP on
+// ACME_HTTP_PORT, port 80 by default.
+
+const http = require("node:http");
+const https = require("node:https");
+const acme = require("acme-client");
+{{ if .Next }}
+const next = require("next");
+
+const app = next({ dev: process.env.NODE_ENV !== "production" });
+const handler = app.getRequestHandler();
+{{- else if .Main }}
+// your request handler, such as an Express app
+const handler = require({{ printf "%q" .Main }});
+{{- else }}
+// replace with your request handler, such as an Express app
+const handler = (req, res) => res.end("Hello from lcl.host!\n");
+{{- end }}
+
+const serverNames = [ {{- range $i, $name := .ServerNames }}{{ if $i }}, {{ end }}{{ printf "%q" $name }}{{ end -}} ];
+const port = {{ .Port }};
+
+const renewInterval = 12 * 60 * 60 * 1000;
+
+const challenges = new Map();
+
+async function provision() {
+  const client = new acme.Client({
+    directoryUrl: process.env.ACME_DIRECTORY_URL,
+    accountKey: await acme.crypto.createPrivateKey(),
+    externalAccountBinding: {
+      kid: process.env.ACME_KID,
+      hmacKey: process.env.ACME_HMAC_KEY,
+    },
+  });
+
+  const [key, csr] = await acme.crypto.createCsr({
+    commonName: serverNames[0],
+    altNames: serverNames,
+  });
+
+  const challengeServer = http.createServer((req, res) => {
+    const token = req.url.split("/").pop();
+    res.end(challenges.get(token) || "");
+  });
+  challengeServer.listen(process.env.ACME_HTTP_PORT || 80);
+
+  try {
+    const cert = await client.auto({
+      csr,
+      email: process.env.ACME_CONTACT,
+      termsOfServiceAgreed: true,
+      challengePriority: ["http-01"],
+      challengeCreateFn: async (authz, challenge, keyAuthorization) => {
+        challenges.set(challenge.token, keyAuthorization);
+      },
+      challengeRemoveFn: async (authz, challenge) => {
+        challenges.delete(challenge.token);
+      },
+    });
+    return { key, cert };
+  } finally {
+    challengeServer.close();
+  }
+}
+
+async function main() {
+{{- if .Next }}
+  await app.prepare();
+{{ end }}
+  const server = https.createServer(await provision(), handler);
+  server.listen(port, () => {
+    console.log(` + "`Listening on https://${serverNames[0]}:${port}`" + `);
+  });
+
+  setInterval(async () => server.setSecureContext(await provision()), renewInterval);
+}
+
+main().catch((err) => {
+  console.error(err);
+  process.exit(1);
+});
+`))
+
+func nodeHTTPS(fsys FS, params Params) (*Integration, error) {
+	main, err := packageMain(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return generateNode(fsys, params, "Node.js HTTPS", false, main)
+}
+
+func nextHTTPS(fsys FS, params Params) (*Integration, error) {
+	return generateNode(fsys, params, "Next.js HTTPS", true, "")
+}
+
+func generateNode(fsys FS, params Params, name string, next bool, main string) (*Integration, error) {
+	var buf bytes.Buffer
+	err := nodeHTTPSTmpl.Execute(&buf, struct {
+		Params
+		Next bool
+		Main string
+	}{params, next, main})
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := create(fsys, nodeHTTPSFile, 0644, buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Integration{
+		Name:  name,
+		Files: files,
+		Steps: []string{
+			"Run `npm install acme-client` to add the ACME client dependency.",
+			"Add the ACME_* variables from `anchor lcl env` to your environment.",
+			"Run `node " + nodeHTTPSFile + "` to start your application.",
+		},
+	}, nil
+}
+
+// packageMain returns the main module of package.json as a relative require
+// path, or "" when there is none.
+func packageMain(fsys FS) (string, error) {
+	data, err := fs.ReadFile(fsys, "package.json")
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var pkg struct {
+		Main string `json:"main"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil || pkg.Main == "" {
+		return "", nil // npm reports invalid package.json better than we can
+	}
+	return "./" + path.Clean(pkg.Main), nil
+}
@@ -0,0 +1,104 @@
+package integration
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const djangoRunserverFile = "anchor-runserver.sh"
+
+func djangoRunserver(fsys FS, params Params) (*Integration, error) {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "#!/bin/sh")
+	fmt.Fprintln(&buf, "# Generated by `anchor lcl setup`. You may edit it.")
+	fmt.Fprintln(&buf, "#")
+	fmt.Fprintln(&buf, "# Serves your Django application over HTTPS with the lcl.host certificate from")
+	fmt.Fprintln(&buf, "# Anchor. The certificate is not renewed automatically, run `anchor lcl mkcert`")
+	fmt.Fprintln(&buf, "# for a new one when it expires.")
+	fmt.Fprintln(&buf)
+	fmt.Fprintf(&buf, "exec python manage.py runserver_plus --cert-file %s --key-file %s 127.0.0.1:%d \"$@\"\n",
+		params.CertFile, params.KeyFile, params.Port)
+
+	files, err := create(fsys, djangoRunserverFile, 0755, buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Integration{
+		Name:      "Django runserver_plus",
+		Files:     files,
+		CertFiles: true,
+		Steps: []string{
+			"Run `pip install django-extensions Werkzeug pyOpenSSL` and add \"django_extensions\" to INSTALLED_APPS.",
+			"Add \".lcl.host\" to ALLOWED_HOSTS in your settings.",
+			"Run `./" + djangoRunserverFile + "` to start your application.",
+		},
+	}, nil
+}
+
+const flaskEnvFile = ".flaskenv"
+
+func flaskRun(fsys FS, params Params) (*Integration, error) {
+	vars := [][2]string{
+		{"FLASK_RUN_CERT", params.CertFile},
+		{"FLASK_RUN_KEY", params.KeyFile},
+		{"FLASK_RUN_HOST", "127.0.0.1"},
+		{"FLASK_RUN_PORT", strconv.Itoa(params.Port)},
+	}
+
+	file, ok, err := change(fsys, flaskEnvFile, 0644, func(old []byte) []byte {
+		return setEnvVars(old, vars)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var files []File
+	if ok {
+		files = append(files, file)
+	}
+
+	return &Integration{
+		Name:      "Flask run",
+		Files:     files,
+		CertFiles: true,
+		Steps: []string{
+			"Run `pip install python-dotenv` so `flask run` loads " + flaskEnvFile + ".",
+			"Run `flask run` to start your application.",
+			"The certificate is not renewed automatically, run `anchor lcl mkcert` for a new one when it expires.",
+		},
+	}, nil
+}
+
+// setEnvVars sets vars in the dotenv file data, replacing the lines of vars
+// that are already set and appending the others.
+func setEnvVars(data []byte, vars [][2]string) []byte {
+	var (
+		out  bytes.Buffer
+		seen = make(map[string]bool, len(vars))
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		name, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(line), "export "), "=")
+		for _, v := range vars {
+			if strings.TrimSpace(name) == v[0] {
+				line = v[0] + "=" + v[1]
+				seen[v[0]] = true
+			}
+		}
+		fmt.Fprintln(&out, line)
+	}
+
+	for _, v := range vars {
+		if !seen[v[0]] {
+			fmt.Fprintf(&out, "%s=%s\n", v[0], v[1])
+		}
+	}
+	return out.Bytes()
+}
@@ -0,0 +1,65 @@
+package integration
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const railsPumaFile = "config/puma.rb"
+
+func railsPuma(fsys FS, params Params) (*Integration, error) {
+	file, ok, err := change(fsys, railsPumaFile, 0644, func(old []byte) []byte {
+		if bytes.Contains(old, []byte("plugin :acme")) {
+			return old
+		}
+
+		var buf bytes.Buffer
+		buf.Write(old)
+		if len(old) > 0 {
+			if !bytes.HasSuffix(old, []byte("\n")) {
+				buf.WriteString("\n")
+			}
+			buf.WriteString("\n")
+		}
+
+		names := make([]string, 0, len(params.ServerNames))
+		for _, name := range params.ServerNames {
+			names = append(names, strconv.Quote(name))
+		}
+
+		fmt.Fprintln(&buf, "# Added by `anchor lcl setup`: serves HTTPS with lcl.host certificates provisioned")
+		fmt.Fprintln(&buf, "# and renewed by Anchor over ACME, with the ACME_* variables from `anchor lcl env`.")
+		fmt.Fprintln(&buf, "plugin :acme")
+		fmt.Fprintln(&buf)
+		fmt.Fprintf(&buf, "acme_server_name %s\n", strings.Join(names, ", "))
+		fmt.Fprintln(&buf, "acme_tos_agreed true")
+		fmt.Fprintln(&buf, `acme_directory ENV["ACME_DIRECTORY_URL"]`)
+		fmt.Fprintln(&buf, `acme_eab_kid ENV["ACME_KID"]`)
+		fmt.Fprintln(&buf, `acme_eab_hmac_key ENV["ACME_HMAC_KEY"]`)
+		fmt.Fprintln(&buf, `acme_contact ENV["ACME_CONTACT"]`)
+		fmt.Fprintln(&buf)
+		fmt.Fprintf(&buf, "bind \"acme://127.0.0.1:%d\"\n", params.Port)
+		return buf.Bytes()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var files []File
+	if ok {
+		files = append(files, file)
+	}
+
+	return &Integration{
+		Name:  "Rails Puma",
+		Files: files,
+		Steps: []string{
+			"Run `bundle add puma-acme` to add the Puma ACME plugin.",
+			"Add `config.hosts << \".lcl.host\"` to config/environments/development.rb.",
+			"Add the ACME_* variables from `anchor lcl env` to your environment.",
+			"Run `bin/rails server` to start your application.",
+		},
+	}, nil
+}
@@ -0,0 +1,186 @@
+package integration
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Servers are the web servers ServerConfig generates config for.
+var Servers = []string{"apache", "caddy", "nginx"}
+
+type serverTemplate struct {
+	name, file string
+
+	tmpl *template.Template
+
+	// certFiles is set for servers that can't provision their own certificate
+	// with ACME.
+	certFiles bool
+	steps     func(path string) []string
+}
+
+var serverTemplates = map[string]serverTemplate{
+	"apache": {
+		name: "Apache",
+		file: "anchor-apache.conf",
+
+		tmpl: template.Must(template.New("apache").Parse(`# lcl.host HTTPS config generated by Anchor. You may edit it.
+#
+# Serves {{ index .ServerNames 0 }} with the lcl.host certificate from Anchor, and
+# requires mod_ssl. The certificate is not renewed automatically, run
+# ` + "`anchor lcl mkcert`" + ` for a new one when it expires.
+
+Listen {{ .Port }}
+
+<VirtualHost *:{{ .Port }}>
+    ServerName {{ index .ServerNames 0 }}
+{{- range slice .ServerNames 1 }}
+    ServerAlias {{ . }}
+{{- end }}
+
+    SSLEngine on
+    SSLCertificateFile {{ .CertFile }}
+    SSLCertificateKeyFile {{ .KeyFile }}
+</VirtualHost>
+`)),
+		certFiles: true,
+		steps: func(path string) []string {
+			return []string{
+				"Add `Include " + path + "` to your Apache config and enable mod_ssl.",
+				"Run `apachectl graceful` to reload Apache.",
+			}
+		},
+	},
+	"caddy": {
+		name: "Caddy",
+		file: "Caddyfile",
+
+		tmpl: template.Must(template.New("caddy").Parse(`# lcl.host HTTPS config generated by Anchor. You may edit it.
+#
+# Serves {{ index .ServerNames 0 }} with lcl.host certificates provisioned and
+# renewed by Anchor over ACME, with the ACME_* variables from ` + "`anchor lcl env`" + `.
+
+{{ range $i, $name := .ServerNames }}{{ if $i }}, {{ end }}{{ $name }}:{{ $.Port }}{{ end }} {
+	tls {
+		ca {{ .ACMEDirectoryURL }}
+		eab {$ACME_KID} {$ACME_HMAC_KEY}
+	}
+
+	respond "Hello from lcl.host!"
+}
+`)),
+		steps: func(path string) []string {
+			return []string{
+				"Add the ACME_* variables from `anchor lcl env` to your environment.",
+				"Run `caddy run --config " + path + "` to start Caddy.",
+			}
+		},
+	},
+	"nginx": {
+		name: "nginx",
+		file: "anchor-nginx.conf",
+
+		tmpl: template.Must(template.New("nginx").Parse(`# lcl.host HTTPS config generated by Anchor. You may edit it.
+#
+# Serves {{ index .ServerNames 0 }} with the lcl.host certificate from Anchor.
+# The certificate is not renewed automatically, run ` + "`anchor lcl mkcert`" + ` for
+# a new one when it expires.
+
+server {
+    listen {{ .Port }} ssl;
+    listen [::]:{{ .Port }} ssl;
+    server_name {{ range $i, $name := .ServerNames }}{{ if $i }} {{ end }}{{ $name }}{{ end }};
+
+    ssl_certificate     {{ .CertFile }};
+    ssl_certificate_key {{ .KeyFile }};
+    ssl_protocols       TLSv1.2 TLSv1.3;
+
+    location / {
+        root html;
+    }
+}
+`)),
+		certFiles: true,
+		steps: func(path string) []string {
+			return []string{
+				"Add `include " + path + ";` to the http block of your nginx config.",
+				"Run `nginx -s reload` to reload nginx.",
+			}
+		},
+	},
+}
+
+// ServerConfig returns the TLS config of the web server for params, as the
+// single file of an integration.
+func ServerConfig(server string, params Params) (*Integration, error) {
+	st, ok := serverTemplates[server]
+	if !ok {
+		return nil, fmt.Errorf("unknown web server %q, expected one of: %s", server, strings.Join(Servers, ", "))
+	}
+	if len(params.ServerNames) == 0 {
+		return nil, fmt.Errorf("no server names for %s config", st.name)
+	}
+
+	var buf bytes.Buffer
+	if err := st.tmpl.Execute(&buf, params); err != nil {
+		return nil, err
+	}
+
+	path := st.file
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	return &Integration{
+		Name:      st.name,
+		Files:     []File{{Path: st.file, Mode: 0644, New: buf.Bytes()}},
+		CertFiles: st.certFiles,
+		Steps:     st.steps(path),
+	}, nil
+}
+
+func serverGenerator(server string) Generator {
+	return func(fsys FS, params Params) (*Integration, error) {
+		// server config is not relative to the project, unlike the certificate
+		// files
+		if abs, err := filepath.Abs(params.CertFile); err == nil && params.CertFile != "" {
+			params.CertFile = abs
+		}
+		if abs, err := filepath.Abs(params.KeyFile); err == nil && params.KeyFile != "" {
+			params.KeyFile = abs
+		}
+
+		in, err := ServerConfig(server, params)
+		if err != nil {
+			return nil, err
+		}
+
+		config := in.Files[0]
+		file, ok, err := change(fsys, config.Path, config.Mode, func(old []byte) []byte {
+			if server != "caddy" || old == nil {
+				return config.New
+			}
+
+			// add the site to an existing Caddyfile, once
+			if bytes.Contains(old, []byte(params.ServerNames[0]+":")) {
+				return old
+			}
+			if !bytes.HasSuffix(old, []byte("\n")) {
+				old = append(old, '\n')
+			}
+			return append(append(old, '\n'), config.New...)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		in.Files = nil
+		if ok {
+			in.Files = append(in.Files, file)
+		}
+		return in, nil
+	}
+}
@@ -0,0 +1,86 @@
+package integration
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anchordotdev/cli/clitest"
+	_ "github.com/anchordotdev/cli/testflags"
+	"github.com/anchordotdev/cli/ui/uitest"
+)
+
+var serverParams = Params{
+	ServerNames:      []string{"test-app.lcl.host", "test-app.localhost"},
+	Port:             4433,
+	ACMEDirectoryURL: "https://anchor.dev/test-org/localhost/x509/ca/acme",
+	CertFile:         "/home/test/app/test-app.lcl.host+1-chain.pem",
+	KeyFile:          "/home/test/app/test-app.lcl.host+1-key.pem",
+}
+
+func TestServerConfig(t *testing.T) {
+	for _, server := range Servers {
+		t.Run(server, func(t *testing.T) {
+			in, err := ServerConfig(server, serverParams)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			uitest.TestGolden(t, string(in.Files[0].New))
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := ServerConfig("lighttpd", serverParams)
+		if want, got := `unknown web server "lighttpd", expected one of: apache, caddy, nginx`, err.Error(); want != got {
+			t.Errorf("want error %q, got %q", want, got)
+		}
+	})
+}
+
+func TestServerGenerator(t *testing.T) {
+	t.Run("caddy-existing", func(t *testing.T) {
+		fsys := clitest.TestFS{
+			"Caddyfile": {Data: []byte("other.lcl.host {\n\trespond \"other\"\n}")},
+		}
+
+		in, err := serverGenerator("caddy")(fsys, serverParams)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data := string(in.Files[0].New)
+		if !strings.HasPrefix(data, "other.lcl.host {\n\trespond \"other\"\n}\n\n# lcl.host HTTPS config") {
+			t.Errorf("want site appended to Caddyfile, got:\n%s", data)
+		}
+
+		if err := in.Write(fsys); err != nil {
+			t.Fatal(err)
+		}
+
+		in, err = serverGenerator("caddy")(fsys, serverParams)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(in.Files) > 0 {
+			t.Errorf("want no changes once written, got:\n%s", in.Diff())
+		}
+	})
+
+	t.Run("nginx-relative-cert-files", func(t *testing.T) {
+		params := serverParams
+		params.CertFile = "./test-app.lcl.host+1-chain.pem"
+
+		in, err := serverGenerator("nginx")(clitest.TestFS{}, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !in.CertFiles {
+			t.Error("want cert files for nginx")
+		}
+
+		data := string(in.Files[0].New)
+		if strings.Contains(data, "ssl_certificate     ./") {
+			t.Errorf("want absolute certificate path, got:\n%s", data)
+		}
+	})
+}
@@ -0,0 +1,14 @@
+// Package backend names the keyring backends, so the config can list them
+// without importing the keyring package.
+package backend
+
+const (
+	File   = "file"
+	Gopass = "gopass"
+	Helper = "helper"
+	Pass   = "pass"
+	System = "system"
+)
+
+// Names are the backends that can be selected with keyring.backend.
+var Names = []string{System, File, Pass, Gopass, Helper}
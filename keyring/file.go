@@ -0,0 +1,264 @@
+package keyring
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/gofrs/flock"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	fileFormatVersion = 1
+
+	fileAdditionalData = "anchor keyring v1"
+)
+
+var ErrFileDecrypt = errors.New("unable to decrypt keyring file, check ANCHOR_KEYRING_PASSPHRASE")
+
+// fileBackend stores secrets in a single file encrypted with XChaCha20-Poly1305.
+// The key is derived with scrypt from Passphrase, or from machine & user
+// identifiers when no passphrase is configured. A machine derived key only
+// protects against the file being copied to another machine or user.
+type fileBackend struct {
+	Path       string
+	Passphrase string
+}
+
+type fileEnvelope struct {
+	Version    int    `json:"version"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// service => user => secret
+type fileSecrets map[string]map[string]string
+
+func (b *fileBackend) Name() string { return BackendFile }
+
+func (b *fileBackend) Delete(service, user string) error {
+	return b.update(func(secrets fileSecrets) error {
+		if _, ok := secrets[service][user]; !ok {
+			return ErrNotFound
+		}
+
+		delete(secrets[service], user)
+		if len(secrets[service]) == 0 {
+			delete(secrets, service)
+		}
+		return nil
+	})
+}
+
+func (b *fileBackend) Get(service, user string) (string, error) {
+	path, err := b.path()
+	if err != nil {
+		return "", err
+	}
+
+	secrets, err := b.read(path)
+	if err != nil {
+		return "", err
+	}
+
+	secret, ok := secrets[service][user]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (b *fileBackend) Set(service, user, secret string) error {
+	return b.update(func(secrets fileSecrets) error {
+		if secrets[service] == nil {
+			secrets[service] = make(map[string]string)
+		}
+		secrets[service][user] = secret
+		return nil
+	})
+}
+
+func (b *fileBackend) update(fn func(fileSecrets) error) error {
+	path, err := b.path()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	secrets, err := b.read(path)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(secrets); err != nil {
+		return err
+	}
+
+	return b.write(path, secrets)
+}
+
+func (b *fileBackend) read(path string) (fileSecrets, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return make(fileSecrets), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var env fileEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("invalid keyring file %s: %w", path, err)
+	}
+	if env.Version != fileFormatVersion {
+		return nil, fmt.Errorf("unsupported keyring file version %d in %s", env.Version, path)
+	}
+
+	aead, err := b.aead(env.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, env.Nonce, env.Ciphertext, []byte(fileAdditionalData))
+	if err != nil {
+		return nil, ErrFileDecrypt
+	}
+
+	secrets := make(fileSecrets)
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+func (b *fileBackend) write(path string, secrets fileSecrets) error {
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	env := fileEnvelope{
+		Version: fileFormatVersion,
+		Salt:    make([]byte, 16),
+		Nonce:   make([]byte, chacha20poly1305.NonceSizeX),
+	}
+	if _, err := rand.Read(env.Salt); err != nil {
+		return err
+	}
+	if _, err := rand.Read(env.Nonce); err != nil {
+		return err
+	}
+
+	aead, err := b.aead(env.Salt)
+	if err != nil {
+		return err
+	}
+	env.Ciphertext = aead.Seal(nil, env.Nonce, plaintext, []byte(fileAdditionalData))
+
+	data, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0600); err != nil && runtime.GOOS != "windows" {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (b *fileBackend) aead(salt []byte) (cipher.AEAD, error) {
+	passphrase := b.Passphrase
+	if passphrase == "" {
+		var err error
+		if passphrase, err = machinePassphrase(); err != nil {
+			return nil, err
+		}
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, err
+	}
+	return chacha20poly1305.NewX(key)
+}
+
+func (b *fileBackend) path() (string, error) {
+	if b.Path != "" {
+		return b.Path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "anchor", "credentials"), nil
+}
+
+var machineIDPaths = []string{
+	"/etc/machine-id",
+	"/var/lib/dbus/machine-id",
+}
+
+func machinePassphrase() (string, error) {
+	u, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+
+	var machineID []byte
+	for _, path := range machineIDPaths {
+		if machineID, err = os.ReadFile(path); err == nil {
+			break
+		}
+	}
+	if len(machineID) == 0 {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return "", err
+		}
+		machineID = []byte(hostname)
+	}
+
+	sum := sha256.Sum256(bytes.Join([][]byte{
+		bytes.TrimSpace(machineID),
+		[]byte(u.Uid),
+		[]byte(strings.ToLower(u.Username)),
+	}, []byte{0}))
+	return fmt.Sprintf("%x", sum), nil
+}
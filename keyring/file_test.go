@@ -0,0 +1,46 @@
+package keyring
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFileBackend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+
+	backend := &fileBackend{
+		Path:       path,
+		Passphrase: "open sesame",
+	}
+
+	if _, err := backend.Get("test-service", "test-user"); err != ErrNotFound {
+		t.Fatalf("want read from empty file error %q, got %q", ErrNotFound, err)
+	}
+
+	if err := backend.Set("test-service", "test-user", "s3cr3t"); err != nil {
+		t.Fatal(err)
+	}
+
+	val, err := backend.Get("test-service", "test-user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "s3cr3t", val; want != got {
+		t.Errorf("want read after file write value %q, got %q", want, got)
+	}
+
+	wrong := &fileBackend{
+		Path:       path,
+		Passphrase: "wrong passphrase",
+	}
+	if _, err := wrong.Get("test-service", "test-user"); err != ErrFileDecrypt {
+		t.Errorf("want read with wrong passphrase error %q, got %q", ErrFileDecrypt, err)
+	}
+
+	if err := backend.Delete("test-service", "test-user"); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := ErrNotFound, backend.Delete("test-service", "test-user"); want != got {
+		t.Errorf("want delete for unset file error %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,92 @@
+package keyring
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// helperBackend delegates storage to an external credential helper executable.
+// The helper is run as `<helper> get|store|erase` with a JSON helperRequest on
+// stdin. For get, it must write a JSON helperResponse to stdout, an empty
+// secret means the credential was not found. For erase, it writes a
+// helperResponse with not_found set when there was no credential, or nothing.
+// A non-zero exit status is an error.
+type helperBackend struct {
+	Command string
+}
+
+type helperRequest struct {
+	Service string `json:"service"`
+	User    string `json:"user"`
+	Secret  string `json:"secret,omitempty"`
+}
+
+type helperResponse struct {
+	Secret   string `json:"secret"`
+	NotFound bool   `json:"not_found,omitempty"`
+}
+
+func (b helperBackend) Name() string { return BackendHelper }
+
+func (b helperBackend) Delete(service, user string) error {
+	out, err := b.exec("erase", helperRequest{Service: service, User: user})
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil
+	}
+
+	var res helperResponse
+	if err := json.Unmarshal(out, &res); err != nil {
+		return fmt.Errorf("invalid keyring helper response: %w", err)
+	}
+	if res.NotFound {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (b helperBackend) Get(service, user string) (string, error) {
+	out, err := b.exec("get", helperRequest{Service: service, User: user})
+	if err != nil {
+		return "", err
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return "", ErrNotFound
+	}
+
+	var res helperResponse
+	if err := json.Unmarshal(out, &res); err != nil {
+		return "", fmt.Errorf("invalid keyring helper response: %w", err)
+	}
+	if res.Secret == "" {
+		return "", ErrNotFound
+	}
+	return res.Secret, nil
+}
+
+func (b helperBackend) Set(service, user, secret string) error {
+	_, err := b.exec("store", helperRequest{Service: service, User: user, Secret: secret})
+	return err
+}
+
+func (b helperBackend) exec(action string, req helperRequest) ([]byte, error) {
+	var stdin, stdout, stderr bytes.Buffer
+	if err := json.NewEncoder(&stdin).Encode(req); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(b.Command, action)
+	cmd.Stdin = &stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("keyring helper %s failed: %w: %s", action, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,34 @@
+package keyring
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestHelperBackendDelete(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test helper is a shell script")
+	}
+
+	path := filepath.Join(t.TempDir(), "anchor-keyring-helper")
+	script := `#!/bin/sh
+if grep -q '"service":"stored"' -; then
+	exit 0
+fi
+echo '{"not_found":true}'
+`
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	backend := helperBackend{Command: path}
+
+	if err := backend.Delete("stored", "test-user"); err != nil {
+		t.Errorf("want delete of stored credential to succeed, got %q", err)
+	}
+	if err := backend.Delete("missing", "test-user"); err != ErrNotFound {
+		t.Errorf("want delete of missing credential error %q, got %q", ErrNotFound, err)
+	}
+}
@@ -1,12 +1,16 @@
 package keyring
 
 import (
+	"errors"
+	"fmt"
 	"os/user"
+	"path/filepath"
 	"sync"
 
 	"github.com/zalando/go-keyring"
 
 	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/keyring/backend"
 )
 
 var ErrNotFound = keyring.ErrNotFound
@@ -17,54 +21,175 @@ const (
 	APIToken label = "API Token"
 )
 
+const (
+	BackendFile   = backend.File
+	BackendGopass = backend.Gopass
+	BackendHelper = backend.Helper
+	BackendPass   = backend.Pass
+	BackendSystem = backend.System
+)
+
+var Backends = backend.Names
+
+// Backend is a credential store. Implementations return ErrNotFound when a
+// secret is missing for Get and Delete.
+type Backend interface {
+	Name() string
+
+	Delete(service, user string) error
+	Get(service, user string) (string, error)
+	Set(service, user, secret string) error
+}
+
+func NewBackend(cfg *cli.Config, name string) (Backend, error) {
+	switch name {
+	case BackendSystem, "":
+		return systemBackend{}, nil
+	case BackendFile:
+		return &fileBackend{
+			Path:       cfg.Keyring.File.Path,
+			Passphrase: cfg.Keyring.File.Passphrase,
+		}, nil
+	case BackendPass, BackendGopass:
+		return passBackend{Command: name}, nil
+	case BackendHelper:
+		if cfg.Keyring.Helper == "" {
+			return nil, cli.UserError{Err: fmt.Errorf("keyring helper backend requires ANCHOR_KEYRING_HELPER or keyring.helper to be set")}
+		}
+		return helperBackend{Command: cfg.Keyring.Helper}, nil
+	default:
+		return nil, cli.UserError{Err: fmt.Errorf("unknown keyring backend %q, expected one of: %v", name, Backends)}
+	}
+}
+
 type Keyring struct {
 	Config *cli.Config
 
-	inito sync.Once
+	Backend Backend // defaults to the configured backend
+
+	inito   sync.Once
+	initErr error
 }
 
-func (k *Keyring) init() {
+func (k *Keyring) init() error {
 	k.inito.Do(func() {
 		if k.Config.Keyring.MockMode {
 			keyring.MockInit()
 		}
+
+		if k.Backend == nil {
+			k.Backend, k.initErr = NewBackend(k.Config, k.Config.Keyring.Backend)
+		}
 	})
+	return k.initErr
 }
 
 func (k *Keyring) Delete(id label) error {
-	k.init()
+	if err := k.init(); err != nil {
+		return err
+	}
 
 	u, err := user.Current()
 	if err != nil {
 		return err
 	}
 
-	return keyring.Delete(k.service(id), u.Username)
+	return k.Backend.Delete(k.service(id), u.Username)
 }
 
 func (k *Keyring) Get(id label) (string, error) {
-	k.init()
+	if err := k.init(); err != nil {
+		return "", err
+	}
 
 	u, err := user.Current()
 	if err != nil {
 		return "", err
 	}
 
-	return keyring.Get(k.service(id), u.Username)
+	return k.Backend.Get(k.service(id), u.Username)
 }
 
 func (k *Keyring) Set(id label, secret string) error {
-	k.init()
+	if err := k.init(); err != nil {
+		return err
+	}
+
+	u, err := user.Current()
+	if err != nil {
+		return err
+	}
+
+	return k.Backend.Set(k.service(id), u.Username, secret)
+}
+
+// Migrate moves the secret for id from this keyring's backend into dst. Once
+// the secret is stored in dst, commit is called to record the move, such as in
+// the config. The secret is only removed from the source after commit
+// succeeds, and is removed from dst instead when it fails.
+func (k *Keyring) Migrate(dst Backend, id label, commit func() error) error {
+	if err := k.init(); err != nil {
+		return err
+	}
+
+	if SameBackend(k.Backend, dst) {
+		return nil
+	}
 
 	u, err := user.Current()
 	if err != nil {
 		return err
 	}
 
-	return keyring.Set(k.service(id), u.Username, secret)
+	secret, err := k.Backend.Get(k.service(id), u.Username)
+	if err != nil {
+		return err
+	}
+
+	if err := dst.Set(k.service(id), u.Username, secret); err != nil {
+		return err
+	}
+
+	if err := commit(); err != nil {
+		if derr := dst.Delete(k.service(id), u.Username); derr != nil {
+			return errors.Join(err, derr)
+		}
+		return err
+	}
+
+	return k.Backend.Delete(k.service(id), u.Username)
+}
+
+// SameBackend reports whether a and b store secrets in the same place, such as
+// file backends with the same path.
+func SameBackend(a, b Backend) bool {
+	if a.Name() != b.Name() {
+		return false
+	}
+
+	fa, aok := a.(*fileBackend)
+	fb, bok := b.(*fileBackend)
+	if aok && bok {
+		pathA, errA := fa.path()
+		pathB, errB := fb.path()
+		return errA == nil && errB == nil && filepath.Clean(pathA) == filepath.Clean(pathB)
+	}
+	return true
 }
 
 func (k *Keyring) service(id label) string {
 	url := k.Config.API.URL
 	return url + " " + string(id)
 }
+
+type systemBackend struct{}
+
+func (systemBackend) Name() string { return BackendSystem }
+
+func (systemBackend) Delete(service, user string) error { return keyring.Delete(service, user) }
+
+func (systemBackend) Get(service, user string) (string, error) { return keyring.Get(service, user) }
+
+func (systemBackend) Set(service, user, secret string) error {
+	return keyring.Set(service, user, secret)
+}
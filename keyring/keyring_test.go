@@ -1,6 +1,9 @@
 package keyring
 
 import (
+	"errors"
+	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/anchordotdev/cli"
@@ -43,3 +46,129 @@ func TestKeyring(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestKeyringMigrate(t *testing.T) {
+	cfg := new(cli.Config)
+	cfg.Keyring.MockMode = true
+	cfg.API.URL = "http://test-keyring-migrate.example.com/"
+
+	kr := &Keyring{Config: cfg}
+
+	if err := kr.Set(APIToken, "open sesame"); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := &fileBackend{
+		Path:       filepath.Join(t.TempDir(), "credentials"),
+		Passphrase: "test",
+	}
+
+	if err := kr.Migrate(dst, APIToken, commitNoop); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := kr.Get(APIToken); err != keyring.ErrNotFound {
+		t.Errorf("want read from migrated keyring error %q, got %q", keyring.ErrNotFound, err)
+	}
+
+	krFile := &Keyring{Config: cfg, Backend: dst}
+
+	val, err := krFile.Get(APIToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "open sesame", val; want != got {
+		t.Errorf("want read after migrate value %q, got %q", want, got)
+	}
+}
+
+func TestKeyringMigrateFilePath(t *testing.T) {
+	cfg := new(cli.Config)
+	cfg.API.URL = "http://test-keyring-migrate-file.example.com/"
+
+	src := &fileBackend{
+		Path:       filepath.Join(t.TempDir(), "credentials"),
+		Passphrase: "test",
+	}
+	dst := &fileBackend{
+		Path:       filepath.Join(t.TempDir(), "credentials"),
+		Passphrase: "test",
+	}
+
+	kr := &Keyring{Config: cfg, Backend: src}
+	if err := kr.Set(APIToken, "open sesame"); err != nil {
+		t.Fatal(err)
+	}
+
+	// same backend name, different path
+	if err := kr.Migrate(dst, APIToken, commitNoop); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := kr.Get(APIToken); err != keyring.ErrNotFound {
+		t.Errorf("want read from migrated keyring error %q, got %q", keyring.ErrNotFound, err)
+	}
+
+	krDst := &Keyring{Config: cfg, Backend: dst}
+
+	val, err := krDst.Get(APIToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "open sesame", val; want != got {
+		t.Errorf("want read after migrate value %q, got %q", want, got)
+	}
+
+	// same backend and path
+	if err := krDst.Migrate(&fileBackend{Path: dst.Path, Passphrase: "test"}, APIToken, commitNoop); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := krDst.Get(APIToken); err != nil {
+		t.Errorf("want secret kept when migrating to the same file, got %q", err)
+	}
+}
+
+func TestKeyringMigrateCommitError(t *testing.T) {
+	cfg := new(cli.Config)
+	cfg.API.URL = "http://test-keyring-migrate-commit.example.com/"
+
+	src := &fileBackend{
+		Path:       filepath.Join(t.TempDir(), "credentials"),
+		Passphrase: "test",
+	}
+	dst := &fileBackend{
+		Path:       filepath.Join(t.TempDir(), "credentials"),
+		Passphrase: "test",
+	}
+
+	kr := &Keyring{Config: cfg, Backend: src}
+	if err := kr.Set(APIToken, "open sesame"); err != nil {
+		t.Fatal(err)
+	}
+
+	errCommit := errors.New("config not written")
+	if err := kr.Migrate(dst, APIToken, func() error { return errCommit }); !errors.Is(err, errCommit) {
+		t.Fatalf("want migrate error %q, got %q", errCommit, err)
+	}
+
+	val, err := kr.Get(APIToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "open sesame", val; want != got {
+		t.Errorf("want source value kept after failed commit %q, got %q", want, got)
+	}
+
+	krDst := &Keyring{Config: cfg, Backend: dst}
+	if _, err := krDst.Get(APIToken); err != keyring.ErrNotFound {
+		t.Errorf("want read from destination after failed commit error %q, got %q", keyring.ErrNotFound, err)
+	}
+}
+
+func commitNoop() error { return nil }
+
+func TestBackendsConfigEnum(t *testing.T) {
+	if !slices.Equal(Backends, cli.ConfigEnums["keyring.backend"]) {
+		t.Errorf("want keyring.backend enum %v, got %v", Backends, cli.ConfigEnums["keyring.backend"])
+	}
+}
@@ -0,0 +1,83 @@
+package keyring
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// passBackend stores secrets with the pass (https://www.passwordstore.org) or
+// gopass password managers, which share a compatible command line interface.
+type passBackend struct {
+	Command string
+}
+
+var passEntryUnwantedRegex = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func (b passBackend) Name() string { return b.Command }
+
+func (b passBackend) Delete(service, user string) error {
+	// without --force, rm of a missing entry fails as not in the password store
+	_, err := b.exec(strings.NewReader("y\n"), "rm", b.entry(service, user))
+	return err
+}
+
+func (b passBackend) Get(service, user string) (string, error) {
+	args := []string{"show", b.entry(service, user)}
+	if b.Command == BackendGopass {
+		args = []string{"show", "--password", b.entry(service, user)}
+	}
+
+	out, err := b.exec(nil, args...)
+	if err != nil {
+		return "", err
+	}
+
+	secret, _, _ := strings.Cut(string(out), "\n")
+	return secret, nil
+}
+
+func (b passBackend) Set(service, user, secret string) error {
+	args := []string{"insert", "--multiline", "--force", b.entry(service, user)}
+	if b.Command == BackendGopass {
+		args = []string{"insert", "--force", b.entry(service, user)}
+	}
+
+	_, err := b.exec(strings.NewReader(secret+"\n"), args...)
+	return err
+}
+
+func (b passBackend) entry(service, user string) string {
+	return "anchor/" + passEntryUnwantedRegex.ReplaceAllString(service, "_") + "/" + passEntryUnwantedRegex.ReplaceAllString(user, "_")
+}
+
+func (b passBackend) exec(stdin *strings.Reader, args ...string) ([]byte, error) {
+	path, err := exec.LookPath(b.Command)
+	if err != nil {
+		return nil, fmt.Errorf("%s keyring backend requires %q to be installed: %w", b.Command, b.Command, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(path, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+
+	if err := cmd.Run(); err != nil {
+		if isPassNotFound(stderr.String()) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("%s %s failed: %w: %s", b.Command, args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+func isPassNotFound(stderr string) bool {
+	stderr = strings.ToLower(stderr)
+	return strings.Contains(stderr, "is not in the password store") || strings.Contains(stderr, "not found")
+}
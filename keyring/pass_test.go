@@ -0,0 +1,36 @@
+package keyring
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestPassBackendDelete(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test pass is a shell script")
+	}
+
+	dir := t.TempDir()
+	script := `#!/bin/sh
+if [ "$1" = rm ] && [ "$2" = anchor/stored/test-user ]; then
+	exit 0
+fi
+echo "Error: $2 is not in the password store." >&2
+exit 1
+`
+	if err := os.WriteFile(filepath.Join(dir, "pass"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+
+	backend := passBackend{Command: BackendPass}
+
+	if err := backend.Delete("stored", "test-user"); err != nil {
+		t.Errorf("want delete of stored entry to succeed, got %q", err)
+	}
+	if err := backend.Delete("missing", "test-user"); err != ErrNotFound {
+		t.Errorf("want delete of missing entry error %q, got %q", ErrNotFound, err)
+	}
+}
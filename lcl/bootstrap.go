@@ -280,20 +280,16 @@ func (c Bootstrap) checkHTTP(ctx context.Context, cfg *cli.Config, drv *ui.Drive
 
 	drv.Send(models.OpenURLMsg(httpURL.String()))
 
-	select {
-	case <-httpConfirmCh:
-	case <-ctx.Done():
-		return false, ctx.Err()
-	}
-
-	var browserless bool
-	if !cfg.Trust.MockMode {
-		if err := browser.OpenURL(httpURL.String()); err != nil {
-			browserless = true
-			drv.Activate(ctx, models.Browserless)
+	if !cfg.NonInteractive {
+		select {
+		case <-httpConfirmCh:
+		case <-ctx.Done():
+			return false, ctx.Err()
 		}
 	}
 
+	browserless := c.openURL(ctx, cfg, drv, httpURL)
+
 	var requestedScheme string
 	if !browserless {
 		select {
@@ -331,20 +327,16 @@ func (c Bootstrap) checkHTTPS(ctx context.Context, cfg *cli.Config, drv *ui.Driv
 
 	drv.Send(models.OpenURLMsg(httpsURL.String()))
 
-	select {
-	case <-httpsConfirmCh:
-	case <-ctx.Done():
-		return ctx.Err()
-	}
-
-	var browserless bool
-	if !cfg.Trust.MockMode {
-		if err := browser.OpenURL(httpsURL.String()); err != nil {
-			browserless = true
-			drv.Activate(ctx, models.Browserless)
+	if !cfg.NonInteractive {
+		select {
+		case <-httpsConfirmCh:
+		case <-ctx.Done():
+			return ctx.Err()
 		}
 	}
 
+	browserless := c.openURL(ctx, cfg, drv, httpsURL)
+
 	var requestedScheme string
 	if !browserless {
 		for requestedScheme != "https" {
@@ -363,7 +355,32 @@ func (c Bootstrap) checkHTTPS(ctx context.Context, cfg *cli.Config, drv *ui.Driv
 	return nil
 }
 
+// openURL opens u in a browser for the diagnostic server to see, and reports
+// whether the browser-based verification has to be skipped. Non-interactive
+// runs skip it, since nobody would visit the URL.
+func (c Bootstrap) openURL(ctx context.Context, cfg *cli.Config, drv *ui.Driver, u *url.URL) (browserless bool) {
+	if cfg.NonInteractive {
+		drv.Activate(ctx, models.BrowserSkip)
+		return true
+	}
+	if cfg.Trust.MockMode {
+		return false
+	}
+	if err := browser.OpenURL(u.String()); err != nil {
+		drv.Activate(ctx, models.Browserless)
+		return true
+	}
+	return false
+}
+
 func (c Bootstrap) diagnosticServiceName(ctx context.Context, drv *ui.Driver, defaultSubdomain string) (string, error) {
+	input := cli.Input{Name: "diagnostic lcl.host domain", ID: cli.AnswerDiagnosticDomain, Default: defaultSubdomain}
+	if answer, ok, err := cli.Answer(ctx, input); err != nil {
+		return "", err
+	} else if ok {
+		return strings.TrimSuffix(answer, ".lcl.host"), nil
+	}
+
 	inputc := make(chan string)
 	drv.Activate(ctx, &models.DomainInput{
 		InputCh: inputc,
@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/api"
 	"github.com/anchordotdev/cli/cmdtest"
 	"github.com/anchordotdev/cli/truststore"
 	"github.com/anchordotdev/cli/ui/uitest"
@@ -164,3 +165,33 @@ func TestBootstrap(t *testing.T) {
 		uitest.TestGolden(t, drv.Golden())
 	})
 }
+
+func TestBootstrapNonInteractive(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cfg := new(cli.Config)
+	cfg.NonInteractive = true
+	cfg.Trust.MockMode = true
+	ctx = cli.ContextWithConfig(ctx, cfg)
+
+	drv, tm := uitest.TestTUI(ctx, t)
+
+	srv := &api.Service{Slug: "hi-ankydotdev"}
+
+	// nobody visits the diagnostic server, so no request is ever sent
+	requestc := make(chan string)
+
+	t.Run("http", func(t *testing.T) {
+		ok, err := Bootstrap{}.checkHTTP(ctx, cfg, drv, srv, "4433", requestc)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("https", func(t *testing.T) {
+		require.NoError(t, Bootstrap{}.checkHTTPS(ctx, cfg, drv, srv, "4433", requestc))
+	})
+
+	require.NoError(t, tm.Quit())
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net"
 	"slices"
+	"strings"
 
 	"github.com/anchordotdev/cli"
 	"github.com/anchordotdev/cli/api"
@@ -170,15 +171,59 @@ func (c *Command) appSetup(ctx context.Context, drv *ui.Driver) error {
 	return cmdSetup.perform(ctx, drv)
 }
 
+// wildcardSampleLabel stands in for the wildcard label of a domain when it
+// needs to be resolved or browsed to.
+const wildcardSampleLabel = "sample"
+
+func isWildcardDomain(domain string) bool {
+	return strings.HasPrefix(domain, "*.")
+}
+
+// sampleDomain returns domain with a sample label in place of its wildcard, or
+// domain when it is not a wildcard.
+func sampleDomain(domain string) string {
+	if isWildcardDomain(domain) {
+		return wildcardSampleLabel + strings.TrimPrefix(domain, "*")
+	}
+	return domain
+}
+
+// checkDomains returns an error for domains a certificate can't be issued for,
+// such as a wildcard that is not the leftmost label.
+func checkDomains(domains []string) error {
+	for _, domain := range domains {
+		name := strings.TrimPrefix(domain, "*.")
+		if strings.Contains(name, "*") {
+			return cli.UserError{Err: fmt.Errorf("%s domain is invalid, a wildcard must be the whole leftmost label, like *.app.lcl.host", domain)}
+		}
+		if isWildcardDomain(domain) && (name == "lcl.host" || name == "localhost") {
+			return cli.UserError{Err: fmt.Errorf("%s domain is too broad, use a wildcard under your own subdomain, like *.app.%s", domain, name)}
+		}
+	}
+	return nil
+}
+
+// checkLoopbackDomain checks domain resolves to a loopback address. Wildcard
+// domains are checked with a sample label.
 func checkLoopbackDomain(ctx context.Context, drv *ui.Driver, domain string) error {
+	domain = sampleDomain(domain)
+
 	drv.Activate(ctx, &models.DomainResolver{
 		Domain: domain,
 	})
 
-	addrs, err := new(net.Resolver).LookupHost(ctx, domain)
-	if err != nil {
+	if err := resolveLoopbackDomain(ctx, domain); err != nil {
 		drv.Send(models.DomainStatusMsg(false))
+		return err
+	}
+	drv.Send(models.DomainStatusMsg(true))
 
+	return nil
+}
+
+func resolveLoopbackDomain(ctx context.Context, domain string) error {
+	addrs, err := new(net.Resolver).LookupHost(ctx, domain)
+	if err != nil {
 		var dnserr *net.DNSError
 		if errors.As(err, &dnserr) {
 			return cli.UserError{Err: errors.New("no such host")}
@@ -189,12 +234,8 @@ func checkLoopbackDomain(ctx context.Context, drv *ui.Driver, domain string) err
 
 	for _, addr := range addrs {
 		if !slices.Contains(loopbackAddrs, addr) {
-			drv.Send(models.DomainStatusMsg(false))
-
 			return fmt.Errorf("%s domain resolved to non-loopback interface address: %s", domain, addr)
 		}
 	}
-	drv.Send(models.DomainStatusMsg(true))
-
 	return nil
 }
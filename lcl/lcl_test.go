@@ -8,6 +8,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -393,3 +394,45 @@ func TestLcl(t *testing.T) {
 		uitest.TestGolden(t, drv.Golden())
 	})
 }
+
+func TestCheckDomains(t *testing.T) {
+	tests := []struct {
+		domains []string
+		err     string
+	}{
+		{
+			domains: []string{"app.lcl.host", "*.app.lcl.host", "app.localhost"},
+		},
+		{
+			domains: []string{"app.lcl.host", "api.app.lcl.host", "admin.app.lcl.host"},
+		},
+		{
+			domains: []string{"app.lcl.host", "api.*.app.lcl.host"},
+			err:     "api.*.app.lcl.host domain is invalid, a wildcard must be the whole leftmost label, like *.app.lcl.host",
+		},
+		{
+			domains: []string{"*app.lcl.host"},
+			err:     "*app.lcl.host domain is invalid, a wildcard must be the whole leftmost label, like *.app.lcl.host",
+		},
+		{
+			domains: []string{"*.lcl.host"},
+			err:     "*.lcl.host domain is too broad, use a wildcard under your own subdomain, like *.app.lcl.host",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(strings.Join(test.domains, ","), func(t *testing.T) {
+			err := checkDomains(test.domains)
+			if test.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestSampleDomain(t *testing.T) {
+	require.Equal(t, "sample.app.lcl.host", sampleDomain("*.app.lcl.host"))
+	require.Equal(t, "app.lcl.host", sampleDomain("app.lcl.host"))
+}
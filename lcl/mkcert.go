@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/anchordotdev/cli"
 	"github.com/anchordotdev/cli/api"
@@ -122,7 +123,7 @@ func (c *MkCert) perform(ctx context.Context, cfg *cli.Config, drv *ui.Driver) (
 		return nil, err
 	}
 
-	subCaAPID, err := c.subcaAPID(ctx, cfg, orgAPID, realmAPID, chainAPID, serviceAPID)
+	subCaAPID, err := c.subcaAPID(ctx, cfg, orgAPID, realmAPID, chainAPID, serviceAPID, domains)
 	if err != nil {
 		return nil, err
 	}
@@ -134,7 +135,7 @@ func (c *MkCert) perform(ctx context.Context, cfg *cli.Config, drv *ui.Driver) (
 
 	acmeURL := cfg.AcmeURL(orgAPID, realmAPID, chainAPID)
 
-	tlsCert, err := api.ProvisionCert(c.eab, domains, acmeURL)
+	tlsCert, err := api.ProvisionCert(ctx, c.eab, domains, acmeURL)
 	if err != nil {
 		return nil, err
 	}
@@ -147,10 +148,14 @@ func (c *MkCert) domains(ctx context.Context, cfg *cli.Config) ([]string, error)
 		return c.Domains, nil
 	}
 
-	c.Domains = cfg.Lcl.MkCert.Domains
-	if len(c.Domains) == 0 {
+	domains := cfg.Lcl.MkCert.Domains
+	if len(domains) == 0 {
 		return nil, cli.UserError{Err: errors.New("domains is required")}
 	}
+	if err := checkDomains(domains); err != nil {
+		return nil, err
+	}
+	c.Domains = domains
 	return c.Domains, nil
 }
 
@@ -256,7 +261,10 @@ func (c *MkCert) serviceAPID(ctx context.Context, cfg *cli.Config, drv *ui.Drive
 	return c.ServiceAPID, nil
 }
 
-func (c *MkCert) subcaAPID(ctx context.Context, cfg *cli.Config, orgAPID, realmAPID, chainAPID, serviceAPID string) (string, error) {
+// subcaAPID returns the sub CA of the service attachment, first adding any of
+// the domains the attachment is missing, since only attached domains are
+// issued certificates.
+func (c *MkCert) subcaAPID(ctx context.Context, cfg *cli.Config, orgAPID, realmAPID, chainAPID, serviceAPID string, domains []string) (string, error) {
 	if c.SubCaAPID != "" {
 		return c.SubCaAPID, nil
 	}
@@ -274,7 +282,19 @@ func (c *MkCert) subcaAPID(ctx context.Context, cfg *cli.Config, orgAPID, realmA
 	for _, a := range attachments {
 		if a.Relationships.Realm.Apid == realmAPID && a.Relationships.Chain.Apid == chainAPID {
 			c.SubCaAPID = cfg.Lcl.MkCert.SubCa
-			return *a.Relationships.SubCa.Apid, nil
+
+			missing := slices.DeleteFunc(slices.Clone(domains), func(domain string) bool {
+				return slices.Contains(a.Domains, domain)
+			})
+			if len(missing) == 0 {
+				return *a.Relationships.SubCa.Apid, nil
+			}
+
+			atch, err := c.anc.AttachService(ctx, chainAPID, append(slices.Clone(a.Domains), missing...), orgAPID, realmAPID, serviceAPID)
+			if err != nil {
+				return "", err
+			}
+			return atch.Relationships.SubCa.Slug, nil
 		}
 	}
 
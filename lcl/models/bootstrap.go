@@ -38,6 +38,13 @@ var (
 			ui.Warning("Unable to open browser, skipping browser-based verification."),
 		},
 	}
+
+	BrowserSkip = ui.Section{
+		Name: "BrowserSkip",
+		Model: ui.MessageLines{
+			ui.StepHint("Running non-interactively, skipping browser-based verification."),
+		},
+	}
 )
 
 type Bootstrap struct {
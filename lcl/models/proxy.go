@@ -0,0 +1,95 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/anchordotdev/cli/ui"
+)
+
+var (
+	ProxyHeader = ui.Section{
+		Name: "ProxyHeader",
+		Model: ui.MessageLines{
+			ui.Header(fmt.Sprintf("Proxy lcl.host HTTPS to a Local Application %s", ui.Whisper("`anchor lcl proxy`"))),
+		},
+	}
+
+	ProxyHint = ui.Section{
+		Name: "ProxyHint",
+		Model: ui.MessageLines{
+			ui.StepHint("We'll serve your application over HTTPS with an lcl.host certificate that"),
+			ui.StepHint("renews automatically, without changing its config."),
+		},
+	}
+)
+
+type ProxyServing struct {
+	URL    string
+	Target string
+
+	RedirectHTTP bool
+
+	Expires time.Time
+
+	renewErr error
+	stopped  bool
+
+	spinner spinner.Model
+}
+
+type ProxyRenewedMsg time.Time
+
+type ProxyRenewErrMsg struct{ Err error }
+
+type ProxyStoppedMsg struct{}
+
+func (m *ProxyServing) Init() tea.Cmd {
+	m.spinner = ui.WaitingSpinner()
+
+	return m.spinner.Tick
+}
+
+func (m *ProxyServing) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ProxyRenewedMsg:
+		m.Expires = time.Time(msg)
+		m.renewErr = nil
+		return m, nil
+	case ProxyRenewErrMsg:
+		m.renewErr = msg.Err
+		return m, nil
+	case ProxyStoppedMsg:
+		m.stopped = true
+		return m, nil
+	default:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+}
+
+func (m *ProxyServing) View() string {
+	var b strings.Builder
+
+	if m.stopped {
+		fmt.Fprintln(&b, ui.StepDone(fmt.Sprintf("Stopped proxying %s to %s.", ui.URL(m.URL), ui.URL(m.Target))))
+		return b.String()
+	}
+
+	fmt.Fprintln(&b, ui.StepInProgress(fmt.Sprintf("Proxying %s to %s…%s", ui.URL(m.URL), ui.URL(m.Target), m.spinner.View())))
+	if m.RedirectHTTP {
+		fmt.Fprintln(&b, ui.StepHint("Redirecting HTTP requests to HTTPS."))
+	}
+	fmt.Fprintln(&b, ui.StepHint(fmt.Sprintf("Certificate renews automatically, current one expires %s.", m.Expires.Format(time.DateTime))))
+	if m.renewErr != nil {
+		fmt.Fprintln(&b, ui.StepAlert(fmt.Sprintf("Failed to renew certificate, retrying: %s", m.renewErr)))
+	}
+	fmt.Fprintln(&b, ui.StepHint("Press Ctrl+C to stop."))
+
+	return b.String()
+}
@@ -13,6 +13,17 @@ import (
 	"github.com/anchordotdev/cli/ui"
 )
 
+const (
+	CertStyleACME      = "acme"
+	CertStyleAnchor    = "anchor"
+	CertStyleAutomated = "automated"
+	CertStyleManual    = "manual"
+	CertStyleMkcert    = "mkcert"
+)
+
+// CertStyles are the cert styles that can be selected with service.cert-style.
+var CertStyles = []string{CertStyleACME, CertStyleAnchor, CertStyleAutomated, CertStyleManual, CertStyleMkcert}
+
 var (
 	SetupHeader = ui.Section{
 		Name: "SetupHeader",
@@ -295,11 +306,11 @@ type SetupMethod struct {
 func (m *SetupMethod) Init() tea.Cmd {
 	m.list = ui.List([]ui.ListItem[string]{
 		{
-			Key:    "automated",
+			Key:    CertStyleAutomated,
 			String: "ACME Automated - Anchor style guides you through setup and automates renewal - Recommended",
 		},
 		{
-			Key:    "manual",
+			Key:    CertStyleManual,
 			String: "Manual - mkcert style leaves setup and renewal up to you",
 		},
 	})
@@ -413,3 +424,140 @@ func (m *SetupGuideHint) View() string {
 
 	return b.String()
 }
+
+type SetupIntegration struct {
+	ChoiceCh chan<- string
+
+	Name string
+	Diff string
+
+	list   list.Model
+	choice string
+}
+
+func (m *SetupIntegration) Init() tea.Cmd {
+	m.list = ui.List([]ui.ListItem[string]{
+		{
+			Key:    "write",
+			String: "Write - add these changes to your project - Recommended",
+		},
+		{
+			Key:    "skip",
+			String: "Skip - follow the setup guide instead",
+		},
+	})
+	return nil
+}
+
+func (m *SetupIntegration) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyEnter:
+			if item, ok := m.list.SelectedItem().(ui.ListItem[string]); ok {
+				m.choice = item.Key
+				if m.ChoiceCh != nil {
+					m.ChoiceCh <- m.choice
+					close(m.ChoiceCh)
+					m.ChoiceCh = nil
+				}
+			}
+		case tea.KeyEsc:
+			return m, ui.Exit
+		}
+	}
+
+	return m, cmd
+}
+
+func (m *SetupIntegration) View() string {
+	var b strings.Builder
+
+	if m.ChoiceCh != nil {
+		fmt.Fprintln(&b, ui.StepHint(fmt.Sprintf("We generated a %s integration for your application:", ui.Emphasize(m.Name))))
+		for _, line := range strings.Split(strings.TrimSuffix(m.Diff, "\n"), "\n") {
+			fmt.Fprintln(&b, ui.StepHint(line))
+		}
+		fmt.Fprintln(&b, ui.StepPrompt("Would you like to write these changes to your project?"))
+		fmt.Fprintln(&b, m.list.View())
+		return b.String()
+	}
+
+	if m.choice == "write" {
+		fmt.Fprintln(&b, ui.StepDone(fmt.Sprintf("Wrote %s integration to your project.", ui.Emphasize(m.Name))))
+	} else {
+		fmt.Fprintln(&b, ui.StepDone(fmt.Sprintf("Skipped %s integration.", ui.Emphasize(m.Name))))
+	}
+	return b.String()
+}
+
+type SetupIntegrationHint struct {
+	Steps  []string
+	LclUrl string
+}
+
+func (m *SetupIntegrationHint) Init() tea.Cmd { return nil }
+
+func (m *SetupIntegrationHint) Update(msg tea.Msg) (tea.Model, tea.Cmd) { return m, nil }
+
+func (m *SetupIntegrationHint) View() string {
+	var b strings.Builder
+
+	fmt.Fprintln(&b, ui.Header("Next Steps"))
+	for _, step := range m.Steps {
+		fmt.Fprintln(&b, ui.StepNext(step))
+	}
+	fmt.Fprintln(&b, ui.StepNext(fmt.Sprintf("Then check out your encrypted site at: %s", ui.URL(m.LclUrl))))
+
+	return b.String()
+}
+
+type SetupDatabase struct {
+	Name       string
+	Files      []string
+	ConfigFile string
+	Config     string
+}
+
+func (m *SetupDatabase) Init() tea.Cmd { return nil }
+
+func (m *SetupDatabase) Update(msg tea.Msg) (tea.Model, tea.Cmd) { return m, nil }
+
+func (m *SetupDatabase) View() string {
+	var b strings.Builder
+
+	for _, file := range m.Files {
+		fmt.Fprintln(&b, ui.StepDone(fmt.Sprintf("Wrote %s.", ui.Emphasize(file))))
+	}
+
+	fmt.Fprintln(&b, ui.StepHint(fmt.Sprintf("Add these lines to your %s %s to require TLS:", ui.Emphasize(m.Name), ui.Emphasize(m.ConfigFile))))
+	for _, line := range strings.Split(strings.TrimSuffix(m.Config, "\n"), "\n") {
+		fmt.Fprintln(&b, ui.StepHint(line))
+	}
+
+	return b.String()
+}
+
+type SetupDatabaseHint struct {
+	Steps      []string
+	Connection string
+}
+
+func (m *SetupDatabaseHint) Init() tea.Cmd { return nil }
+
+func (m *SetupDatabaseHint) Update(msg tea.Msg) (tea.Model, tea.Cmd) { return m, nil }
+
+func (m *SetupDatabaseHint) View() string {
+	var b strings.Builder
+
+	fmt.Fprintln(&b, ui.Header("Next Steps"))
+	for _, step := range m.Steps {
+		fmt.Fprintln(&b, ui.StepNext(step))
+	}
+	fmt.Fprintln(&b, ui.StepNext(fmt.Sprintf("Then connect over verified TLS with: %s", ui.URL(m.Connection))))
+
+	return b.String()
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/anchordotdev/cli/ui"
+)
+
+var (
+	UpHeader = ui.Section{
+		Name: "UpHeader",
+		Model: ui.MessageLines{
+			ui.Header(fmt.Sprintf("Provision and Verify lcl.host Workspace Services %s", ui.Whisper("`anchor lcl up`"))),
+		},
+	}
+
+	UpHint = ui.Section{
+		Name: "UpHint",
+		Model: ui.MessageLines{
+			ui.StepHint("We'll provision HTTPS certificates for every service in your workspace, then"),
+			ui.StepHint("verify their domains and certificates."),
+		},
+	}
+)
+
+type UpService struct {
+	Name string
+
+	Domains []string
+
+	err      error
+	finished bool
+
+	spinner spinner.Model
+}
+
+func (m *UpService) Init() tea.Cmd {
+	m.spinner = ui.WaitingSpinner()
+
+	return m.spinner.Tick
+}
+
+type upServiceMsg struct {
+	mdl *UpService
+	err error
+}
+
+func (m *UpService) Done(err error) tea.Msg {
+	return upServiceMsg{
+		mdl: m,
+		err: err,
+	}
+}
+
+func (m *UpService) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case upServiceMsg:
+		if msg.mdl == m {
+			m.finished = true
+			m.err = msg.err
+		}
+		return m, nil
+	default:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+}
+
+func (m *UpService) View() string {
+	var b strings.Builder
+	switch {
+	case !m.finished:
+		fmt.Fprintln(&b, ui.StepInProgress(fmt.Sprintf("Provisioning %s [%s]…%s",
+			ui.Emphasize(m.Name),
+			ui.Domains(m.Domains),
+			m.spinner.View())))
+	case m.err == nil:
+		fmt.Fprintln(&b, ui.StepDone(fmt.Sprintf("Provisioned and verified %s [%s].",
+			ui.Emphasize(m.Name),
+			ui.Domains(m.Domains))))
+	default:
+		fmt.Fprintln(&b, ui.StepAlert(fmt.Sprintf("Failed to provision %s [%s]!",
+			ui.Emphasize(m.Name),
+			ui.Domains(m.Domains))))
+		fmt.Fprintln(&b, ui.StepAlert(fmt.Sprintf("Error! %s",
+			m.err.Error())))
+	}
+	return b.String()
+}
@@ -0,0 +1,258 @@
+package lcl
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/api"
+	"github.com/anchordotdev/cli/auth"
+	"github.com/anchordotdev/cli/lcl/models"
+	"github.com/anchordotdev/cli/proxy"
+	"github.com/anchordotdev/cli/ui"
+)
+
+var CmdLclProxy = cli.NewCmd[Proxy](CmdLcl, "proxy", func(cmd *cobra.Command) {
+	cfg := cli.ConfigFromCmd(cmd)
+
+	cmd.Flags().StringVarP(&cfg.Org.APID, "org", "o", cli.Defaults.Org.APID, "Organization of the proxied service.")
+	cmd.Flags().StringVarP(&cfg.Lcl.RealmAPID, "realm", "r", cli.Defaults.Lcl.RealmAPID, "Realm of the proxied service.")
+	cmd.Flags().StringVarP(&cfg.Service.APID, "service", "s", cli.Defaults.Service.APID, "Service to proxy, created when missing.")
+
+	cmd.Flags().StringVar(&cfg.Lcl.Proxy.To, "to", cli.Defaults.Lcl.Proxy.To, "URL of the application to proxy to, such as http://localhost:3000.")
+	cmd.Flags().StringVar(&cfg.Lcl.Proxy.Domain, "domain", cli.Defaults.Lcl.Proxy.Domain, "lcl.host domain to serve, such as app.lcl.host.")
+	cmd.Flags().StringVar(&cfg.Lcl.Proxy.Listen, "listen", cli.Defaults.Lcl.Proxy.Listen, "Address to serve HTTPS on.")
+	cmd.Flags().BoolVar(&cfg.Lcl.Proxy.RedirectHTTP, "redirect-http", cli.Defaults.Lcl.Proxy.RedirectHTTP, "Redirect HTTP requests to HTTPS, instead of proxying them.")
+})
+
+type Proxy struct {
+	anc *api.Session
+}
+
+func (c Proxy) UI() cli.UI {
+	return cli.UI{
+		RunTUI: c.run,
+	}
+}
+
+func (c *Proxy) run(ctx context.Context, drv *ui.Driver) error {
+	var err error
+	cmd := &auth.Client{
+		Anc:    c.anc,
+		Source: "lclhost",
+	}
+	c.anc, err = cmd.Perform(ctx, drv)
+	if err != nil {
+		return err
+	}
+
+	drv.Activate(ctx, models.ProxyHeader)
+	drv.Activate(ctx, models.ProxyHint)
+
+	return c.perform(ctx, drv)
+}
+
+func (c *Proxy) perform(ctx context.Context, drv *ui.Driver) error {
+	cfg := cli.ConfigFromContext(ctx)
+
+	target, err := proxyTarget(cfg.Lcl.Proxy.To)
+	if err != nil {
+		return err
+	}
+
+	domain, err := proxyDomain(cfg)
+	if err != nil {
+		return err
+	}
+
+	_, port, err := net.SplitHostPort(cfg.Lcl.Proxy.Listen)
+	if err != nil {
+		return cli.UserError{Err: fmt.Errorf("invalid --listen address %q: %w", cfg.Lcl.Proxy.Listen, err)}
+	}
+
+	mkcert := &MkCert{anc: c.anc}
+
+	orgAPID, err := mkcert.orgAPID(ctx, cfg, drv)
+	if err != nil {
+		return err
+	}
+
+	realmAPID, err := mkcert.realmAPID(ctx, cfg, drv, orgAPID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkLoopbackDomain(ctx, drv, domain); err != nil {
+		return err
+	}
+
+	domains := []string{domain, strings.TrimSuffix(domain, ".lcl.host") + ".localhost"}
+
+	serviceAPID := cfg.Service.APID
+	if serviceAPID == "" {
+		serviceAPID = parameterize(strings.TrimPrefix(strings.TrimSuffix(domain, ".lcl.host"), "*."))
+	}
+
+	drv.Activate(ctx, &models.ProvisionService{
+		Name:       serviceAPID,
+		Domains:    domains,
+		ServerType: "custom",
+	})
+
+	srv, err := c.anc.GetService(ctx, orgAPID, serviceAPID)
+	if err != nil {
+		return err
+	}
+	if srv == nil {
+		localhostPort, err := net.LookupPort("tcp", port)
+		if err != nil {
+			return cli.UserError{Err: fmt.Errorf("invalid --listen port %q: %w", port, err)}
+		}
+		if srv, err = c.anc.CreateService(ctx, orgAPID, serviceAPID, "custom", &localhostPort); err != nil {
+			return err
+		}
+	}
+
+	// FIXME: we need to lookup and pass the chain and/or make it non-optional
+	chainAPID := "ca"
+
+	atch, err := c.anc.AttachService(ctx, chainAPID, domains, orgAPID, realmAPID, srv.Slug)
+	if err != nil {
+		return err
+	}
+
+	mkcert.Domains = domains
+	mkcert.ServiceAPID = srv.Slug
+	mkcert.ChainAPID = atch.Relationships.Chain.Slug
+	mkcert.SubCaAPID = atch.Relationships.SubCa.Slug
+
+	tlsCert, err := mkcert.perform(ctx, cfg, drv)
+	if err != nil {
+		return err
+	}
+	drv.Send(models.ServiceProvisionedMsg{})
+
+	var cert atomic.Pointer[tls.Certificate]
+	cert.Store(tlsCert)
+
+	server := &proxy.Server{
+		Addr:         cfg.Lcl.Proxy.Listen,
+		Target:       target,
+		RedirectHTTP: cfg.Lcl.Proxy.RedirectHTTP,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return cert.Load(), nil
+		},
+	}
+	if err := server.Start(ctx); err != nil {
+		return err
+	}
+	defer server.Close()
+
+	if _, port, err = net.SplitHostPort(server.Addr); err != nil {
+		return err
+	}
+
+	drv.Activate(ctx, &models.ProxyServing{
+		URL:          fmt.Sprintf("https://%s:%s", sampleDomain(domain), port),
+		Target:       target.String(),
+		RedirectHTTP: cfg.Lcl.Proxy.RedirectHTTP,
+		Expires:      tlsCert.Leaf.NotAfter,
+	})
+
+	next := renewAt(tlsCert)
+	for {
+		select {
+		case <-time.After(time.Until(next)):
+		case <-ctx.Done():
+			drv.Send(models.ProxyStoppedMsg{})
+			return nil
+		}
+
+		renewed, err := mkcert.perform(ctx, cfg, drv)
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			drv.Send(models.ProxyRenewErrMsg{Err: err})
+
+			// retry, as long as the current certificate is still valid
+			if time.Now().After(cert.Load().Leaf.NotAfter) {
+				return err
+			}
+			next = time.Now().Add(time.Minute)
+			continue
+		}
+
+		cert.Store(renewed)
+		next = renewAt(renewed)
+		drv.Send(models.ProxyRenewedMsg(renewed.Leaf.NotAfter))
+	}
+}
+
+// renewAt returns when to renew cert, a third of its lifetime before it
+// expires.
+func renewAt(cert *tls.Certificate) time.Time {
+	lifetime := cert.Leaf.NotAfter.Sub(cert.Leaf.NotBefore)
+	return cert.Leaf.NotAfter.Add(-lifetime / 3)
+}
+
+// proxyTarget returns the URL of --to, defaulting to http for a bare address
+// like localhost:3000.
+func proxyTarget(to string) (*url.URL, error) {
+	if to == "" {
+		return nil, cli.UserError{Err: errors.New("--to is required, such as --to http://localhost:3000")}
+	}
+	if !strings.Contains(to, "://") {
+		to = "http://" + to
+	}
+
+	target, err := url.Parse(to)
+	if err != nil {
+		return nil, cli.UserError{Err: fmt.Errorf("invalid --to URL: %w", err)}
+	}
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return nil, cli.UserError{Err: fmt.Errorf("invalid --to URL %s, the scheme must be http or https", to)}
+	}
+	if target.Hostname() == "" {
+		target.Host = "localhost" + target.Host // such as :3000
+	}
+	return target, nil
+}
+
+// proxyDomain returns the lcl.host domain of --domain, or the configured
+// service, or one named for the current directory.
+func proxyDomain(cfg *cli.Config) (string, error) {
+	domain := cfg.Lcl.Proxy.Domain
+	if domain == "" {
+		for _, d := range cfg.Service.Domains {
+			if isLclDomain(d) {
+				domain = d
+				break
+			}
+		}
+	}
+	if domain == "" {
+		path, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		domain = parameterize(filepath.Base(path))
+	}
+
+	domain = strings.TrimSuffix(domain, ".lcl.host") + ".lcl.host"
+	if err := checkDomains([]string{domain}); err != nil {
+		return "", err
+	}
+	return domain, nil
+}
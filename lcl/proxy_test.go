@@ -0,0 +1,72 @@
+package lcl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchordotdev/cli/cmdtest"
+)
+
+func TestCmdLclProxy(t *testing.T) {
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdLclProxy, "lcl", "proxy", "--help")
+	})
+
+	t.Run("default --listen", func(t *testing.T) {
+		cfg := cmdtest.TestCfg(t, CmdLclProxy)
+		require.Equal(t, ":4443", cfg.Lcl.Proxy.Listen)
+	})
+
+	t.Run("--to http://localhost:3000", func(t *testing.T) {
+		cfg := cmdtest.TestCfg(t, CmdLclProxy, "--to", "http://localhost:3000")
+		require.Equal(t, "http://localhost:3000", cfg.Lcl.Proxy.To)
+	})
+
+	t.Run("--domain app.lcl.host", func(t *testing.T) {
+		cfg := cmdtest.TestCfg(t, CmdLclProxy, "--domain", "app.lcl.host")
+		require.Equal(t, "app.lcl.host", cfg.Lcl.Proxy.Domain)
+	})
+
+	t.Run("--listen :8443", func(t *testing.T) {
+		cfg := cmdtest.TestCfg(t, CmdLclProxy, "--listen", ":8443")
+		require.Equal(t, ":8443", cfg.Lcl.Proxy.Listen)
+	})
+
+	t.Run("PROXY_LISTEN=:9443", func(t *testing.T) {
+		t.Setenv("PROXY_LISTEN", ":9443")
+
+		cfg := cmdtest.TestCfg(t, CmdLclProxy)
+		require.Equal(t, ":9443", cfg.Lcl.Proxy.Listen)
+	})
+
+	t.Run("--redirect-http", func(t *testing.T) {
+		cfg := cmdtest.TestCfg(t, CmdLclProxy, "--redirect-http")
+		require.True(t, cfg.Lcl.Proxy.RedirectHTTP)
+	})
+}
+
+func TestProxyTarget(t *testing.T) {
+	tests := []struct {
+		to, want, err string
+	}{
+		{to: "http://localhost:3000", want: "http://localhost:3000"},
+		{to: "localhost:5173", want: "http://localhost:5173"},
+		{to: ":6006", want: "http://localhost:6006"},
+		{to: "https://127.0.0.1:8443/app", want: "https://127.0.0.1:8443/app"},
+		{to: "ftp://localhost:21", err: "invalid --to URL ftp://localhost:21, the scheme must be http or https"},
+		{to: "", err: "--to is required, such as --to http://localhost:3000"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.to, func(t *testing.T) {
+			target, err := proxyTarget(test.to)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.want, target.String())
+		})
+	}
+}
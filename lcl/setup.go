@@ -8,6 +8,8 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"slices"
 	"strings"
 
 	"github.com/cli/browser"
@@ -20,6 +22,7 @@ import (
 	"github.com/anchordotdev/cli/component"
 	componentmodels "github.com/anchordotdev/cli/component/models"
 	"github.com/anchordotdev/cli/detection"
+	"github.com/anchordotdev/cli/integration"
 	"github.com/anchordotdev/cli/lcl/models"
 	climodels "github.com/anchordotdev/cli/models"
 	"github.com/anchordotdev/cli/org"
@@ -38,6 +41,7 @@ var CmdLclSetup = cli.NewCmd[Setup](CmdLcl, "setup", func(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&cfg.Org.Name, "org-name", "", "Name for created org.")
 	cmd.Flags().StringVarP(&cfg.Lcl.RealmAPID, "realm", "r", cli.Defaults.Lcl.RealmAPID, "Realm for lcl.host application setup.")
 	cmd.Flags().StringVarP(&cfg.Service.APID, "service", "s", cli.Defaults.Service.APID, "Service for lcl.host application setup.")
+	cmd.Flags().StringSliceVar(&cfg.Service.Domains, "domains", cli.Defaults.Service.Domains, "Domains for the service certificate, including wildcards like *.app.lcl.host.")
 
 	// alias
 	cmd.Flags().StringVar(&cfg.Service.Category, "language", cli.Defaults.Service.Category, "Language to integrate with Anchor.")
@@ -47,11 +51,11 @@ var CmdLclSetup = cli.NewCmd[Setup](CmdLcl, "setup", func(cmd *cobra.Command) {
 })
 
 var (
-	MethodACME      = "acme"
-	MethodAnchor    = "anchor"
-	MethodAutomated = "automated"
-	MethodManual    = "manual"
-	MethodMkcert    = "mkcert"
+	MethodACME      = models.CertStyleACME
+	MethodAnchor    = models.CertStyleAnchor
+	MethodAutomated = models.CertStyleAutomated
+	MethodManual    = models.CertStyleManual
+	MethodMkcert    = models.CertStyleMkcert
 )
 
 type Setup struct {
@@ -89,9 +93,106 @@ func (c *Setup) run(ctx context.Context, drv *ui.Driver) error {
 	return nil
 }
 
+// Inputs prompted for by setup, when not configured.
+var (
+	serviceInput     = cli.Input{Name: "service", Flag: "--service", Env: "SERVICE", ID: cli.AnswerService}
+	serviceNameInput = cli.Input{Name: "service name", Env: "SERVICE_NAME", ID: cli.AnswerServiceName}
+	categoryInput    = cli.Input{Name: "service category", Flag: "--category", Env: "SERVICE_CATEGORY", ID: cli.AnswerCategory}
+	domainInput      = cli.Input{Name: "lcl.host domain", ID: cli.AnswerDomain}
+	certStyleInput   = cli.Input{Name: "certificate style", Flag: "--cert-style", Env: "CERT_STYLE", ID: cli.AnswerCertStyle}
+	integrationInput = cli.Input{Name: "framework integration", ID: cli.AnswerIntegration, Default: "skip"}
+)
+
 func (c *Setup) perform(ctx context.Context, drv *ui.Driver) error {
 	cfg := cli.ConfigFromContext(ctx)
 
+	err := c.performSetup(ctx, cfg, drv)
+
+	// list every unanswered prompt, not only the first
+	var merr cli.MissingInputError
+	if errors.As(err, &merr) {
+		if c.ServiceAPID == "" && cfg.Service.APID == "" {
+			if _, _, err := cli.Answer(ctx, serviceInput); err != nil {
+				merr.Add(serviceInput)
+			}
+			merr.Add(c.pendingInputs(ctx, cfg)...)
+		}
+		return merr
+	}
+	return err
+}
+
+// pendingInputs lists the inputs initial setup prompts for that are neither
+// configured nor answered.
+func (c *Setup) pendingInputs(ctx context.Context, cfg *cli.Config) []cli.Input {
+	var inputs []cli.Input
+	if cfg.Service.Category == "" {
+		inputs = append(inputs, categoryInput)
+	}
+	if cfg.Service.Name == "" {
+		inputs = append(inputs, withDefault(serviceNameInput, defaultServiceName()))
+	}
+	if !slices.ContainsFunc(cfg.Service.Domains, isLclDomain) {
+		inputs = append(inputs, withDefault(domainInput, "default"))
+	}
+	if cfg.Service.CertStyle == "" && !integration.IsDatabase(knownCategory(ctx, cfg)) {
+		inputs = append(inputs, certStyleInput)
+	}
+	if c.integrates(ctx, cfg) {
+		inputs = append(inputs, integrationInput)
+	}
+
+	return slices.DeleteFunc(inputs, func(input cli.Input) bool {
+		_, _, err := cli.Answer(ctx, input)
+		return err == nil
+	})
+}
+
+// integrates reports whether setup offers a framework integration, as far as
+// is known before prompting.
+func (c *Setup) integrates(ctx context.Context, cfg *cli.Config) bool {
+	category, certStyle := knownCategory(ctx, cfg), cfg.Service.CertStyle
+	if certStyle == "" {
+		certStyle, _, _ = cli.Answer(ctx, certStyleInput)
+	}
+
+	switch certStyle {
+	case MethodACME, MethodAnchor, MethodAutomated:
+	default:
+		return false
+	}
+
+	_, ok, _ := integration.Detect(cfg.SystemFS(), category)
+	return ok
+}
+
+// knownCategory returns the configured or answered service category, if any.
+func knownCategory(ctx context.Context, cfg *cli.Config) string {
+	if cfg.Service.Category != "" {
+		return cfg.Service.Category
+	}
+	category, _, _ := cli.Answer(ctx, categoryInput)
+	return category
+}
+
+func withDefault(input cli.Input, value string) cli.Input {
+	input.Default = value
+	return input
+}
+
+func isLclDomain(domain string) bool {
+	return strings.HasSuffix(domain, ".lcl.host")
+}
+
+func defaultServiceName() string {
+	path, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(path) // TODO: use detected name recommendation
+}
+
+func (c *Setup) performSetup(ctx context.Context, cfg *cli.Config, drv *ui.Driver) error {
 	orgAPID, err := c.orgAPID(ctx, cfg, drv)
 	if err != nil {
 		return err
@@ -114,6 +215,14 @@ func (c *Setup) perform(ctx context.Context, drv *ui.Driver) error {
 }
 
 func (c *Setup) initialSetup(ctx context.Context, cfg *cli.Config, drv *ui.Driver, orgAPID, realmAPID string) error {
+	// fail before provisioning anything when a later prompt can't be answered
+	if pending := c.pendingInputs(ctx, cfg); len(pending) > 0 {
+		return cli.MissingInputError{Inputs: pending}
+	}
+	if err := checkDomains(cfg.Service.Domains); err != nil {
+		return err
+	}
+
 	// TODO: select name before category
 
 	category, err := c.serviceCategory(ctx, cfg, drv)
@@ -126,16 +235,18 @@ func (c *Setup) initialSetup(ctx context.Context, cfg *cli.Config, drv *ui.Drive
 		return err
 	}
 
-	lclDomain, err := c.serviceDomain(ctx, drv, name)
+	lclDomain, err := c.serviceDomain(ctx, cfg, drv, name)
 	if err != nil {
 		return err
 	}
+	if err := checkDomains([]string{lclDomain}); err != nil {
+		return err
+	}
 	if err := checkLoopbackDomain(ctx, drv, lclDomain); err != nil {
 		return err
 	}
 
-	subdomain := strings.TrimSuffix(lclDomain, ".lcl.host")
-	domains := []string{lclDomain, subdomain + ".localhost"}
+	domains := certDomains(lclDomain, cfg.Service.Domains)
 	port := cfg.LclHostPort()
 
 	drv.Activate(ctx, &models.ProvisionService{
@@ -179,29 +290,49 @@ func (c *Setup) initialSetup(ctx context.Context, cfg *cli.Config, drv *ui.Drive
 	}
 	drv.Send(models.ServiceProvisionedMsg{})
 
-	certStyle, err := c.certStyle(ctx, cfg, drv)
-	if err != nil {
-		return err
+	// database servers only take certificate files
+	certStyle := MethodMkcert
+	if !integration.IsDatabase(category) {
+		if certStyle, err = c.certStyle(ctx, cfg, drv); err != nil {
+			return err
+		}
 	}
 
 	switch certStyle {
 	case MethodManual, MethodMkcert:
 		certStyle = MethodMkcert
+		if integration.IsDatabase(category) {
+			if err := c.databaseMethod(ctx, cfg, drv, orgAPID, realmAPID, category, tlsCert, domains, *srv.LocalhostPort); err != nil {
+				return err
+			}
+			break
+		}
 		if err := c.manualMethod(ctx, drv, orgAPID, realmAPID, srv.Slug, tlsCert, domains...); err != nil {
 			return err
 		}
 	case MethodACME, MethodAnchor, MethodAutomated:
 		certStyle = MethodACME
-		setupGuideURL := cfg.SetupGuideURL(orgAPID, srv.Slug)
-		lclURL := fmt.Sprintf("https://%s:%d", lclDomain, *srv.LocalhostPort)
-		if err := c.automatedMethod(ctx, cfg, drv, setupGuideURL, lclURL); err != nil {
+		lclURL := fmt.Sprintf("https://%s:%d", sampleDomain(lclDomain), *srv.LocalhostPort)
+		params := integration.Params{
+			ServerNames:      domains,
+			Port:             *srv.LocalhostPort,
+			ACMEDirectoryURL: cfg.AcmeURL(orgAPID, realmAPID, atch.Relationships.Chain.Slug),
+		}
+		integrated, err := c.integrate(ctx, cfg, drv, orgAPID, realmAPID, srv.Slug, category, tlsCert, params, lclURL)
+		if err != nil {
 			return err
 		}
+		if !integrated {
+			setupGuideURL := cfg.SetupGuideURL(orgAPID, srv.Slug)
+			if err := c.automatedMethod(ctx, cfg, drv, setupGuideURL, lclURL); err != nil {
+				return err
+			}
+		}
 	default:
 		return fmt.Errorf("Unknown method: %s. Please choose either `acme` (recommended) or `mkcert`.", certStyle)
 	}
 
-	return c.writeTOML(ctx, cfg, drv, orgAPID, realmAPID, srv.Slug, category, certStyle)
+	return c.writeTOML(ctx, cfg, drv, orgAPID, realmAPID, name, srv.Slug, category, certStyle)
 }
 
 func (c *Setup) orgAPID(ctx context.Context, cfg *cli.Config, drv *ui.Driver) (string, error) {
@@ -328,9 +459,12 @@ func (c *Setup) serviceName(ctx context.Context, cfg *cli.Config, drv *ui.Driver
 		return cfg.Service.Name, nil
 	}
 
-	var defaultName string
-	if path, err := os.Getwd(); err == nil {
-		defaultName = filepath.Base(path) // TODO: use detected name recommendation
+	defaultName := defaultServiceName()
+
+	if answer, ok, err := cli.Answer(ctx, withDefault(serviceNameInput, defaultName)); err != nil {
+		return "", err
+	} else if ok {
+		return answer, nil
 	}
 
 	inputc := make(chan string)
@@ -357,6 +491,11 @@ func (c *Setup) serviceCategory(ctx context.Context, cfg *cli.Config, drv *ui.Dr
 		})
 		return cfg.Service.Category, nil
 	}
+	if answer, ok, err := cli.Answer(ctx, categoryInput); err != nil {
+		return "", err
+	} else if ok {
+		return answer, nil
+	}
 
 	drv.Activate(ctx, &models.SetupScan{})
 
@@ -395,11 +534,32 @@ func (c *Setup) serviceCategory(ctx context.Context, cfg *cli.Config, drv *ui.Dr
 	}
 }
 
-func (c *Setup) serviceDomain(ctx context.Context, drv *ui.Driver, name string) (string, error) {
-	// TODO: check cfg.Lcl.Domain or something
+func (c *Setup) serviceDomain(ctx context.Context, cfg *cli.Config, drv *ui.Driver, name string) (string, error) {
+	// prefer a domain that can be browsed to as is over a wildcard
+	var wildcard string
+	for _, domain := range cfg.Service.Domains {
+		if !isLclDomain(domain) {
+			continue
+		}
+		if !isWildcardDomain(domain) {
+			return domain, nil
+		}
+		if wildcard == "" {
+			wildcard = domain
+		}
+	}
+	if wildcard != "" {
+		return wildcard, nil
+	}
 
 	defaultDomain := parameterize(name)
 
+	if answer, ok, err := cli.Answer(ctx, withDefault(domainInput, defaultDomain)); err != nil {
+		return "", err
+	} else if ok {
+		return strings.TrimSuffix(answer, ".lcl.host") + ".lcl.host", nil
+	}
+
 	inputc := make(chan string)
 	drv.Activate(ctx, &models.DomainInput{
 		InputCh: inputc,
@@ -417,6 +577,20 @@ func (c *Setup) serviceDomain(ctx context.Context, drv *ui.Driver, name string)
 	}
 }
 
+// certDomains returns the domains to certify for lclDomain. Configured
+// domains replace the defaults, but lclDomain is always included since it's
+// used for the lcl URL.
+func certDomains(lclDomain string, configured []string) []string {
+	if len(configured) == 0 {
+		subdomain := strings.TrimSuffix(lclDomain, ".lcl.host")
+		return []string{lclDomain, subdomain + ".localhost"}
+	}
+	if slices.Contains(configured, lclDomain) {
+		return configured
+	}
+	return append([]string{lclDomain}, configured...)
+}
+
 func (c *Setup) certStyle(ctx context.Context, cfg *cli.Config, drv *ui.Driver) (string, error) {
 	if cfg.Service.CertStyle != "" {
 		drv.Activate(ctx, &componentmodels.ConfigVia{
@@ -427,6 +601,11 @@ func (c *Setup) certStyle(ctx context.Context, cfg *cli.Config, drv *ui.Driver)
 		})
 		return cfg.Service.CertStyle, nil
 	}
+	if answer, ok, err := cli.Answer(ctx, certStyleInput); err != nil {
+		return "", err
+	} else if ok {
+		return answer, nil
+	}
 
 	choicec := make(chan string)
 	drv.Activate(ctx, &models.SetupMethod{
@@ -474,6 +653,105 @@ func (c *Setup) manualMethod(ctx context.Context, drv *ui.Driver, orgAPID string
 	return cmdCertProvision.Perform(ctx, drv)
 }
 
+// databaseMethod writes the certificate files of a database server, with the
+// permissions it demands of them, along with the CA bundle of the realm for
+// clients verifying it.
+func (c *Setup) databaseMethod(ctx context.Context, cfg *cli.Config, drv *ui.Driver, orgAPID, realmAPID, category string, tlsCert *tls.Certificate, domains []string, port int) error {
+	creds, err := c.anc.FetchCredentials(ctx, orgAPID, realmAPID)
+	if err != nil {
+		return err
+	}
+
+	var cas []byte
+	for _, cred := range creds {
+		cas = append(cas, cred.TextualEncoding...)
+		if !strings.HasSuffix(cred.TextualEncoding, "\n") {
+			cas = append(cas, '\n')
+		}
+	}
+
+	keyData, err := cert.KeyPEM(tlsCert)
+	if err != nil {
+		return err
+	}
+
+	// database servers resolve relative paths from their data directory
+	_, chainFile, keyFile := cert.Filenames(tlsCert.Leaf.Subject.CommonName, domains)
+	params := integration.Params{
+		ServerNames: domains,
+		Port:        port,
+		CertFile:    chainFile,
+		KeyFile:     keyFile,
+		CAFile:      "./anchor-ca.pem",
+	}
+	for _, path := range []*string{&params.CertFile, &params.KeyFile, &params.CAFile} {
+		if *path, err = filepath.Abs(*path); err != nil {
+			return err
+		}
+	}
+
+	dbTLS, err := integration.DatabaseConfig(category, params, integration.Credentials{
+		Chain: cert.ChainPEM(tlsCert),
+		Key:   keyData,
+		CAs:   cas,
+	})
+	if err != nil {
+		return err
+	}
+
+	var files []string
+	for _, file := range dbTLS.Files {
+		if !cfg.Trust.MockMode {
+			if err := replaceFile(file.Path, file.New, file.Mode); err != nil {
+				return err
+			}
+		}
+		files = append(files, file.Path)
+	}
+
+	drv.Activate(ctx, &models.SetupDatabase{
+		Name:       dbTLS.Name,
+		Files:      files,
+		ConfigFile: dbTLS.ConfigFile,
+		Config:     dbTLS.Config,
+	})
+
+	drv.Activate(ctx, &models.SetupDatabaseHint{
+		Steps: []string{
+			fmt.Sprintf("Anchor does not change file ownership: the files above are owned by you, so %s cannot read the key yet.", dbTLS.Name),
+			fmt.Sprintf("Copy them to a directory %s can read, such as its data directory, run `sudo chown %s` on the copies and update their paths in %s.", dbTLS.Name, dbTLS.User, dbTLS.ConfigFile),
+			fmt.Sprintf("Restart %s to load the certificate.", dbTLS.Name),
+		},
+		Connection: dbTLS.Connection,
+	})
+
+	return nil
+}
+
+// replaceFile writes data to a temporary file with mode and renames it over
+// path, so an existing file, such as a key, never holds the new data with its
+// old mode.
+func replaceFile(path string, data []byte, mode os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(mode); err != nil && runtime.GOOS != "windows" {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
 func (c *Setup) automatedMethod(ctx context.Context, cfg *cli.Config, drv *ui.Driver, setupGuideURL string, LclURL string) error {
 	setupGuideConfirmCh := make(chan struct{})
 
@@ -483,10 +761,12 @@ func (c *Setup) automatedMethod(ctx context.Context, cfg *cli.Config, drv *ui.Dr
 
 	drv.Send(models.OpenSetupGuideMsg(setupGuideURL))
 
-	select {
-	case <-setupGuideConfirmCh:
-	case <-ctx.Done():
-		return ctx.Err()
+	if !cfg.NonInteractive {
+		select {
+		case <-setupGuideConfirmCh:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 
 	if !cfg.Trust.MockMode {
@@ -500,14 +780,99 @@ func (c *Setup) automatedMethod(ctx context.Context, cfg *cli.Config, drv *ui.Dr
 	return nil
 }
 
-func (c *Setup) writeTOML(ctx context.Context, cfg *cli.Config, drv *ui.Driver, orgAPID, realmAPID, serviceAPID, category, certStyle string) error {
+// integrate offers to write the integration generated for the framework of the
+// application, and reports whether it was written. Integrations without ACME
+// support are written along with the certificate files.
+func (c *Setup) integrate(ctx context.Context, cfg *cli.Config, drv *ui.Driver, orgAPID, realmAPID, serviceAPID, category string, tlsCert *tls.Certificate, params integration.Params, lclURL string) (bool, error) {
+	fsys := cfg.SystemFS()
+
+	gen, ok, err := integration.Detect(fsys, category)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	_, params.CertFile, params.KeyFile = cert.Filenames(tlsCert.Leaf.Subject.CommonName, params.ServerNames)
+	in, err := gen(fsys, params)
+	if err != nil {
+		return false, err
+	}
+
+	// with nothing to change, the application is already integrated
+	if len(in.Files) > 0 {
+		choice, err := c.integrationChoice(ctx, drv, in)
+		if err != nil {
+			return false, err
+		}
+
+		switch choice {
+		case "write":
+		case "skip":
+			return false, nil
+		default:
+			return false, fmt.Errorf("Unknown integration choice: %s. Please choose either `write` or `skip`.", choice)
+		}
+
+		if err := in.Write(fsys); err != nil {
+			return false, err
+		}
+	}
+
+	if in.CertFiles {
+		if err := c.manualMethod(ctx, drv, orgAPID, realmAPID, serviceAPID, tlsCert, params.ServerNames...); err != nil {
+			return false, err
+		}
+	}
+
+	drv.Activate(ctx, &models.SetupIntegrationHint{
+		Steps:  in.Steps,
+		LclUrl: lclURL,
+	})
+
+	return true, nil
+}
+
+func (c *Setup) integrationChoice(ctx context.Context, drv *ui.Driver, in *integration.Integration) (string, error) {
+	if answer, ok, err := cli.Answer(ctx, integrationInput); err != nil {
+		return "", err
+	} else if ok {
+		return answer, nil
+	}
+
+	choicec := make(chan string)
+	drv.Activate(ctx, &models.SetupIntegration{
+		ChoiceCh: choicec,
+		Name:     in.Name,
+		Diff:     in.Diff(),
+	})
+
+	select {
+	case choice := <-choicec:
+		return choice, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func (c *Setup) writeTOML(ctx context.Context, cfg *cli.Config, drv *ui.Driver, orgAPID, realmAPID, name, serviceAPID, category, certStyle string) error {
 	cfg = cfg.Copy()
 
 	cfg.Org.APID = orgAPID
 	cfg.Lcl.RealmAPID = realmAPID
-	cfg.Service.APID = serviceAPID
-	cfg.Service.Category = category
-	cfg.Service.CertStyle = certStyle
+
+	if svc, ok := cfg.WorkspaceService(name); ok {
+		svc.APID = serviceAPID
+		svc.Category = category
+		svc.CertStyle = certStyle
+
+		// workspace services are stored in [[services]], not [service]
+		cfg.Service.APID = ""
+		cfg.Service.Category = ""
+		cfg.Service.CertStyle = ""
+	} else {
+		cfg.Service.APID = serviceAPID
+		cfg.Service.Category = category
+		cfg.Service.CertStyle = certStyle
+	}
 
 	if err := cfg.WriteTOML(); err != nil {
 		return err
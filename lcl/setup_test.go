@@ -2,7 +2,15 @@ package lcl
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
+	"math/big"
+	"os"
 	"testing"
 	"time"
 
@@ -12,6 +20,7 @@ import (
 
 	"github.com/anchordotdev/cli"
 	"github.com/anchordotdev/cli/api"
+	"github.com/anchordotdev/cli/cert"
 	"github.com/anchordotdev/cli/clipboard"
 	"github.com/anchordotdev/cli/cmdtest"
 	"github.com/anchordotdev/cli/truststore"
@@ -38,6 +47,11 @@ func TestCmdLclSetup(t *testing.T) {
 		require.Equal(t, "org", cfg.Org.Name)
 	})
 
+	t.Run("--domains app.lcl.host,*.app.lcl.host", func(t *testing.T) {
+		cfg := cmdtest.TestCfg(t, CmdLclSetup, "--domains", "app.lcl.host,*.app.lcl.host")
+		require.Equal(t, []string{"app.lcl.host", "*.app.lcl.host"}, cfg.Service.Domains)
+	})
+
 	// alias
 
 	t.Run("--language python", func(t *testing.T) {
@@ -192,12 +206,6 @@ func TestSetup(t *testing.T) {
 			"? How would you like to manage your lcl.host certificates?",
 		)
 
-		tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
-
-		uitest.WaitForGoldenContains(t, drv, errc,
-			fmt.Sprintf("! Press Enter to open %s in your browser.", setupGuideURL),
-		)
-
 		{
 			anc, err := api.NewClient(ctx, cfg)
 			if err != nil {
@@ -212,10 +220,24 @@ func TestSetup(t *testing.T) {
 			lclUrl := fmt.Sprintf("https://test-app.lcl.host:%d", *srv.LocalhostPort)
 
 			drv.Replace(lclUrl, "https://test-app.lcl.host:<service-port>")
+			drv.Replace(fmt.Sprintf(`":%d"`, *srv.LocalhostPort), `":<service-port>"`)
 		}
 
 		tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
 
+		uitest.WaitForGoldenContains(t, drv, errc,
+			"? Would you like to write these changes to your project?",
+		)
+
+		tm.Send(tea.KeyMsg{Type: tea.KeyDown}) // skip the Go autocert integration
+		tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+
+		uitest.WaitForGoldenContains(t, drv, errc,
+			fmt.Sprintf("! Press Enter to open %s in your browser.", setupGuideURL),
+		)
+
+		tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+
 		tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
 		uitest.TestGolden(t, drv.Golden())
 
@@ -484,3 +506,113 @@ func TestSetup(t *testing.T) {
 		}
 	})
 }
+
+func TestSetupDatabaseMethod(t *testing.T) {
+	if srv.IsProxy() {
+		t.Skip("database setup unsupported in proxy mode")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := new(cli.Config)
+	cfg.API.URL = srv.URL
+	cfg.API.Token = "test-token"
+	ctx = cli.ContextWithConfig(ctx, cfg)
+
+	anc, err := api.NewClient(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tlsCert := testCertificate(t, "db.lcl.host")
+
+	t.Chdir(t.TempDir())
+
+	// an existing key file keeps its mode when written in place
+	_, _, keyFile := cert.Filenames("db.lcl.host", []string{"db.lcl.host"})
+	if err := os.WriteFile(keyFile, []byte("old key"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	drv, tm := uitest.TestTUI(ctx, t)
+
+	cmd := Setup{anc: anc}
+	if err := cmd.databaseMethod(ctx, cfg, drv, "org-slug", "realm-slug", "postgresql", tlsCert, []string{"db.lcl.host"}, 5432); err != nil {
+		t.Fatal(err)
+	}
+
+	// ownership is left to the user, and the hint says so
+	uitest.WaitForGoldenContains(t, drv, nil,
+		"Anchor does not change file ownership",
+	)
+	require.NoError(t, tm.Quit())
+
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modes := make(map[string]os.FileMode)
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			t.Fatal(err)
+		}
+		modes[entry.Name()] = info.Mode().Perm()
+	}
+
+	require.Equal(t, map[string]os.FileMode{
+		"anchor-ca.pem":         0644,
+		"db.lcl.host-chain.pem": 0644,
+		"db.lcl.host-key.pem":   0600,
+	}, modes)
+
+	keyData, err := os.ReadFile(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	require.Contains(t, string(keyData), "PRIVATE KEY")
+}
+
+func testCertificate(t *testing.T, commonName string) *tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}
+}
+
+func TestCertDomains(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		require.Equal(t, []string{"app.lcl.host", "app.localhost"}, certDomains("app.lcl.host", nil))
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		domains := []string{"app.lcl.host", "*.app.lcl.host"}
+		require.Equal(t, domains, certDomains("app.lcl.host", domains))
+	})
+
+	t.Run("configured without lcl domain", func(t *testing.T) {
+		domains := []string{"app.localhost"}
+		require.Equal(t, []string{"app.lcl.host", "app.localhost"}, certDomains("app.lcl.host", domains))
+	})
+}
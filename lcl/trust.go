@@ -19,7 +19,7 @@ var CmdLclTrust = cli.NewCmd[Trust](CmdLcl, "trust", func(cmd *cobra.Command) {
 	cfg := cli.ConfigFromCmd(cmd)
 
 	cmd.Flags().BoolVar(&cfg.Trust.NoSudo, "no-sudo", false, "Disable sudo prompts.")
-	cmd.Flags().StringSliceVar(&cfg.Trust.Stores, "trust-stores", []string{"homebrew", "nss", "system"}, "Trust stores to update.")
+	cmd.Flags().StringSliceVar(&cfg.Trust.Stores, "trust-stores", []string{truststore.StoreHomebrew, truststore.StoreNSS, truststore.StoreSystem}, "Trust stores to update.")
 })
 
 type Trust struct {
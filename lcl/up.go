@@ -0,0 +1,264 @@
+package lcl
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/api"
+	"github.com/anchordotdev/cli/auth"
+	"github.com/anchordotdev/cli/lcl/models"
+	"github.com/anchordotdev/cli/ui"
+)
+
+var CmdLclUp = cli.NewCmd[Up](CmdLcl, "up", func(cmd *cobra.Command) {
+	cfg := cli.ConfigFromCmd(cmd)
+
+	cmd.Flags().StringVarP(&cfg.Org.APID, "org", "o", cli.Defaults.Org.APID, "Organization of the workspace services.")
+	cmd.Flags().StringVarP(&cfg.Lcl.RealmAPID, "realm", "r", cli.Defaults.Lcl.RealmAPID, "Realm of the workspace services.")
+	cmd.Flags().StringVarP(&cfg.Service.APID, "service", "s", cli.Defaults.Service.APID, "Workspace service to bring up, by name or apid.")
+})
+
+type Up struct {
+	anc *api.Session
+}
+
+func (c Up) UI() cli.UI {
+	return cli.UI{
+		RunTUI: c.run,
+	}
+}
+
+func (c *Up) run(ctx context.Context, drv *ui.Driver) error {
+	cfg := cli.ConfigFromContext(ctx)
+
+	var err error
+	cmd := &auth.Client{
+		Anc:    c.anc,
+		Source: "lclhost",
+	}
+	c.anc, err = cmd.Perform(ctx, drv)
+	if err != nil {
+		return err
+	}
+
+	drv.Activate(ctx, models.UpHeader)
+	drv.Activate(ctx, models.UpHint)
+
+	services, err := workspaceServices(cfg)
+	if err != nil {
+		return err
+	}
+
+	orgAPID := cfg.Org.APID
+	if orgAPID == "" {
+		return cli.UserError{Err: fmt.Errorf("workspace org is required, set apid in the [org] table of %s or use --org", cfg.File.Path)}
+	}
+
+	realmAPID := cfg.Lcl.RealmAPID
+	if realmAPID == "" {
+		realmAPID = "localhost"
+	}
+
+	mdls := make([]*models.UpService, len(services))
+	for i, svc := range services {
+		mdls[i] = &models.UpService{
+			Name:    svc.Name,
+			Domains: serviceDomains(svc),
+		}
+		drv.Activate(ctx, mdls[i])
+	}
+
+	serviceAPIDs := make([]string, len(services))
+	errs := make([]error, len(services))
+
+	var group errgroup.Group
+	for i, svc := range services {
+		group.Go(func() error {
+			serviceAPIDs[i], errs[i] = c.up(ctx, cfg, drv, orgAPID, realmAPID, svc, mdls[i].Domains)
+			drv.Send(mdls[i].Done(errs[i]))
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	if err := c.writeTOML(cfg, services, serviceAPIDs); err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// workspaceServices returns the [[services]] selected by --service, or all of
+// them.
+func workspaceServices(cfg *cli.Config) ([]cli.ServiceConfig, error) {
+	if len(cfg.Services) == 0 {
+		return nil, cli.UserError{Err: fmt.Errorf("no [[services]] found in %s, add an entry for each service of the workspace", cfg.File.Path)}
+	}
+
+	selector := cfg.Service.Name
+	if selector == "" {
+		selector = cfg.Service.APID
+	}
+	if selector == "" {
+		return cfg.Services, nil
+	}
+
+	svc, ok := cfg.WorkspaceService(selector)
+	if !ok {
+		return nil, cli.UserError{Err: fmt.Errorf("%s is not a service of the workspace in %s", selector, cfg.File.Path)}
+	}
+	return []cli.ServiceConfig{*svc}, nil
+}
+
+func serviceDomains(svc cli.ServiceConfig) []string {
+	if len(svc.Domains) > 0 {
+		return svc.Domains
+	}
+
+	subdomain := parameterize(svc.Name)
+	return []string{subdomain + ".lcl.host", subdomain + ".localhost"}
+}
+
+func (c *Up) up(ctx context.Context, cfg *cli.Config, drv *ui.Driver, orgAPID, realmAPID string, svc cli.ServiceConfig, domains []string) (string, error) {
+	serviceAPID := svc.APID
+	if serviceAPID == "" {
+		serviceAPID = svc.Name
+	}
+
+	srv, err := c.anc.GetService(ctx, orgAPID, serviceAPID)
+	if err != nil {
+		return "", err
+	}
+	if srv == nil {
+		if svc.Category == "" {
+			return "", cli.UserError{Err: fmt.Errorf("category is required to create the %s service", svc.Name)}
+		}
+		if srv, err = c.anc.CreateService(ctx, orgAPID, svc.Name, svc.Category, cfg.LclHostPort()); err != nil {
+			return "", err
+		}
+	}
+
+	// FIXME: we need to lookup and pass the chain and/or make it non-optional
+	chainAPID := "ca"
+
+	atch, err := c.anc.AttachService(ctx, chainAPID, domains, orgAPID, realmAPID, srv.Slug)
+	if err != nil {
+		return srv.Slug, err
+	}
+
+	mkcert := &MkCert{
+		anc:         c.anc,
+		Domains:     domains,
+		OrgAPID:     orgAPID,
+		RealmAPID:   realmAPID,
+		ServiceAPID: srv.Slug,
+
+		ChainAPID: atch.Relationships.Chain.Slug,
+		SubCaAPID: atch.Relationships.SubCa.Slug,
+	}
+
+	tlsCert, err := mkcert.perform(ctx, cfg, drv)
+	if err != nil {
+		return srv.Slug, err
+	}
+
+	if err := writeServiceCert(cfg, svc, tlsCert); err != nil {
+		return srv.Slug, err
+	}
+
+	// verify
+
+	for _, domain := range domains {
+		if err := tlsCert.Leaf.VerifyHostname(domain); err != nil {
+			return srv.Slug, err
+		}
+		if strings.HasSuffix(domain, ".lcl.host") {
+			if err := resolveLoopbackDomain(ctx, domain); err != nil {
+				return srv.Slug, fmt.Errorf("%s: %w", domain, err)
+			}
+		}
+	}
+
+	return srv.Slug, nil
+}
+
+func writeServiceCert(cfg *cli.Config, svc cli.ServiceConfig, tlsCert *tls.Certificate) error {
+	if cfg.Trust.MockMode {
+		return nil
+	}
+
+	// output paths are relative to the workspace anchor.toml
+	resolve := func(path string) string {
+		if filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(filepath.Dir(cfg.File.Path), path)
+	}
+
+	if svc.CertFile != "" {
+		var chainData []byte
+		for _, certDER := range tlsCert.Certificate {
+			chainData = append(chainData, pem.EncodeToMemory(&pem.Block{
+				Type:  "CERTIFICATE",
+				Bytes: certDER,
+			})...)
+		}
+
+		if err := os.WriteFile(resolve(svc.CertFile), chainData, 0644); err != nil {
+			return err
+		}
+	}
+
+	if svc.KeyFile != "" {
+		keyDER, err := x509.MarshalPKCS8PrivateKey(tlsCert.PrivateKey)
+		if err != nil {
+			return err
+		}
+
+		keyBlock := &pem.Block{
+			Type:  "PRIVATE KEY",
+			Bytes: keyDER,
+		}
+
+		if err := os.WriteFile(resolve(svc.KeyFile), pem.EncodeToMemory(keyBlock), 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTOML stores the apid of services created by up in their [[services]]
+// entry. Only the config file's own values are written back, so env and flag
+// values and the [service] table are left as they are.
+func (c *Up) writeTOML(cfg *cli.Config, services []cli.ServiceConfig, serviceAPIDs []string) error {
+	fileCfg, err := cfg.ReadTOML()
+	if err != nil {
+		return err
+	}
+
+	var changed bool
+	for i, svc := range services {
+		if serviceAPIDs[i] == "" || serviceAPIDs[i] == svc.APID {
+			continue
+		}
+		if ws, ok := fileCfg.WorkspaceService(svc.Name); ok && ws.APID != serviceAPIDs[i] {
+			ws.APID = serviceAPIDs[i]
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	return fileCfg.WriteTOML()
+}
@@ -0,0 +1,152 @@
+package lcl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/cmdtest"
+	"github.com/go-test/deep"
+)
+
+func TestCmdLclUp(t *testing.T) {
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdLclUp, "lcl", "up", "--help")
+	})
+}
+
+func TestWorkspaceServices(t *testing.T) {
+	services := []cli.ServiceConfig{
+		{Name: "api", APID: "api-svc", Category: "go"},
+		{Name: "web", Category: "ruby", Domains: []string{"web.lcl.host"}},
+	}
+
+	tests := []struct {
+		name string
+
+		cfgFn func(*cli.Config)
+
+		want []string
+		err  bool
+	}{
+		{
+			name:  "all",
+			cfgFn: func(cfg *cli.Config) {},
+			want:  []string{"api", "web"},
+		},
+		{
+			name:  "by-name",
+			cfgFn: func(cfg *cli.Config) { cfg.Service.Name = "web" },
+			want:  []string{"web"},
+		},
+		{
+			name:  "by-apid",
+			cfgFn: func(cfg *cli.Config) { cfg.Service.APID = "api-svc" },
+			want:  []string{"api"},
+		},
+		{
+			name:  "unknown",
+			cfgFn: func(cfg *cli.Config) { cfg.Service.APID = "admin" },
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := new(cli.Config)
+			cfg.Services = services
+			test.cfgFn(cfg)
+
+			got, err := workspaceServices(cfg)
+			if test.err {
+				if err == nil {
+					t.Fatal("want error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var names []string
+			for _, svc := range got {
+				names = append(names, svc.Name)
+			}
+			if diff := deep.Equal(test.want, names); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		if _, err := workspaceServices(new(cli.Config)); err == nil {
+			t.Fatal("want error, got nil")
+		}
+	})
+}
+
+func TestServiceDomains(t *testing.T) {
+	if diff := deep.Equal([]string{"my-app.lcl.host", "my-app.localhost"}, serviceDomains(cli.ServiceConfig{Name: "My App"})); diff != nil {
+		t.Error(diff)
+	}
+	if diff := deep.Equal([]string{"web.lcl.host"}, serviceDomains(cli.ServiceConfig{Name: "web", Domains: []string{"web.lcl.host"}})); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestUpWriteTOML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "anchor.toml")
+
+	toml := heredoc.Doc(`
+		version = 1
+
+		[service]
+		category = "ruby" # used by lcl setup
+
+		[[services]]
+		name = "api"
+
+		[[services]]
+		name = "web"
+		apid = "web-svc"
+	`)
+	if err := os.WriteFile(path, []byte(toml), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := cli.Defaults.Copy()
+	cfg.File.Path = path
+	cfg.Org.APID = "flag-org"
+	cfg.Service.APID = "api-svc"
+	cfg.Service.CertStyle = "acme"
+	cfg.Services = []cli.ServiceConfig{{Name: "api"}, {Name: "web", APID: "web-svc"}}
+
+	if err := new(Up).writeTOML(cfg, cfg.Services, []string{"api-svc", "web-svc"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := heredoc.Doc(`
+		version = 1
+
+		[service]
+		category = "ruby" # used by lcl setup
+
+		[[services]]
+		name = "api"
+		apid = 'api-svc'
+
+		[[services]]
+		name = "web"
+		apid = "web-svc"
+	`)
+	if want, got := want, string(got); want != got {
+		t.Errorf("want config:\n%s\ngot:\n%s", want, got)
+	}
+}
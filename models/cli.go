@@ -44,6 +44,9 @@ func (m *ReportError) View() string {
 	)))
 
 	fmt.Fprintln(&b, ui.StepHint("We are sorry you encountered this error."))
+	fmt.Fprintln(&b, ui.StepHint(fmt.Sprintf("Without a browser, run %s and attach the bundle to an issue instead.",
+		ui.Whisper("`anchor debug bundle`"),
+	)))
 
 	if m.ConfirmCh != nil {
 		fmt.Fprintln(&b, ui.StepAlert(fmt.Sprintf("%s to open an issue on Github.",
@@ -86,6 +86,14 @@ func (c *Create) orgName(ctx context.Context, cfg *cli.Config, drv *ui.Driver) (
 	}
 
 	if c.OrgName == "" {
+		input := cli.Input{Name: "organization name", Flag: "--org-name", Env: "ORG_NAME", ID: cli.AnswerOrgName}
+		if answer, ok, err := cli.Answer(ctx, input); err != nil {
+			return "", err
+		} else if ok {
+			c.OrgName = answer
+			return c.OrgName, nil
+		}
+
 		inputc := make(chan string)
 		drv.Activate(ctx, &models.CreateOrgNameInput{
 			InputCh: inputc,
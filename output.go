@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli/stacktrace"
+	"github.com/anchordotdev/cli/ui"
+)
+
+// Result is the final event of a command in json output mode.
+type Result struct {
+	Type string `json:"type"` // always result
+
+	Command  string `json:"command"`
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+
+	// Output is the plain text output of the command.
+	Output string `json:"output,omitempty"`
+
+	// Result is the value stored by the command with SetResult.
+	Result any `json:"result,omitempty"`
+
+	// Missing lists the inputs needed instead of prompts, when the command
+	// failed because it could not prompt.
+	Missing []Input `json:"missing,omitempty"`
+}
+
+type resultHolder struct {
+	mu    sync.Mutex
+	value any
+}
+
+// SetResult stores v as the result of the running command, reported as the
+// result of json output mode.
+func SetResult(ctx context.Context, v any) {
+	if holder, ok := ctx.Value(resultKey).(*resultHolder); ok {
+		holder.mu.Lock()
+		defer holder.mu.Unlock()
+
+		holder.value = v
+	}
+}
+
+func contextWithResult(ctx context.Context) (context.Context, *resultHolder) {
+	holder := new(resultHolder)
+	return context.WithValue(ctx, resultKey, holder), holder
+}
+
+func checkOutput(cfg *Config) error {
+	switch cfg.Output {
+	case "", "json", "text":
+		return nil
+	default:
+		return UserError{Err: fmt.Errorf("unknown output format %q, expected json or text", cfg.Output)}
+	}
+}
+
+// runOutput runs a TUI command without the TUI, for the json and text output
+// modes.
+func runOutput(ctx context.Context, cmd *cobra.Command, runTUI func(context.Context, *ui.Driver) error) error {
+	cfg := ConfigFromContext(ctx)
+	out := cmd.OutOrStdout()
+
+	ctx, holder := contextWithResult(ctx)
+
+	drv, prg := ui.NewDriverOutput(ctx, cfg.Output, out)
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := prg.Run()
+		errc <- err
+	}()
+
+	err := stacktrace.CapturePanic(func() error { return runTUI(ctx, drv) })
+
+	var uierr ui.Error
+	if errors.As(err, &uierr) {
+		drv.Activate(context.Background(), uierr.Model)
+		err = uierr.Err
+	}
+
+	drv.Program.Quit()
+	if perr := <-errc; err == nil && perr != nil && !errors.Is(perr, context.Canceled) {
+		err = perr
+	}
+
+	if cfg.Output == "text" {
+		if _, werr := io.WriteString(out, drv.PlainView()); werr != nil {
+			return werr
+		}
+		return err
+	}
+	return writeResult(out, cmd, drv.PlainView(), holder, err)
+}
+
+// runCLIOutput runs a plain output command, wrapping its output in a result in
+// json output mode.
+func runCLIOutput(ctx context.Context, cmd *cobra.Command, streams Streams, runCLI func(context.Context, Streams) error) error {
+	cfg := ConfigFromContext(ctx)
+	if cfg.Output != "json" {
+		return stacktrace.CapturePanic(func() error { return runCLI(ctx, streams) })
+	}
+
+	ctx, holder := contextWithResult(ctx)
+
+	out := streams.Out
+	var buf bytes.Buffer
+	streams.Out = &buf
+
+	err := stacktrace.CapturePanic(func() error { return runCLI(ctx, streams) })
+	return writeResult(out, cmd, buf.String(), holder, err)
+}
+
+func writeResult(w io.Writer, cmd *cobra.Command, output string, holder *resultHolder, err error) error {
+	holder.mu.Lock()
+	defer holder.mu.Unlock()
+
+	result := Result{
+		Type:     "result",
+		Command:  cmd.CommandPath(),
+		ExitCode: ExitCode(err),
+		Output:   output,
+		Result:   holder.value,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	var merr MissingInputError
+	if errors.As(err, &merr) {
+		result.Missing = merr.Inputs
+	}
+
+	if werr := json.NewEncoder(w).Encode(result); werr != nil {
+		return werr
+	}
+	return err
+}
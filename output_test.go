@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchordotdev/cli/ui"
+)
+
+type testStatusMsg bool
+
+func testRunTUI(err error) func(context.Context, *ui.Driver) error {
+	return func(ctx context.Context, drv *ui.Driver) error {
+		drv.Activate(ctx, ui.Section{
+			Name:  "TestHeader",
+			Model: ui.MessageLines{ui.Header("Test Output")},
+		})
+		drv.Send(testStatusMsg(true))
+
+		SetResult(ctx, map[string]string{"key": "value"})
+		return err
+	}
+}
+
+func TestRunOutput(t *testing.T) {
+	tests := []struct {
+		name string
+
+		output string
+		err    error
+
+		want string
+	}{
+		{
+			name: "json",
+
+			output: "json",
+
+			want: heredoc.Doc(`
+				{"type":"activate","model":"TestHeader"}
+				{"type":"message","message":"cli.testStatusMsg","data":true}
+				{"type":"result","command":"test","exit_code":0,"output":"# Test Output\n","result":{"key":"value"}}
+			`),
+		},
+		{
+			name: "json-error",
+
+			output: "json",
+			err:    UserError{Err: errors.New("test error")},
+
+			want: heredoc.Doc(`
+				{"type":"activate","model":"TestHeader"}
+				{"type":"message","message":"cli.testStatusMsg","data":true}
+				{"type":"result","command":"test","exit_code":2,"error":"test error","output":"# Test Output\n","result":{"key":"value"}}
+			`),
+		},
+		{
+			name: "json-missing-input",
+
+			output: "json",
+			err:    MissingInputError{Inputs: []Input{{Name: "service category", Flag: "--category", Env: "SERVICE_CATEGORY"}}},
+
+			want: heredoc.Doc(`
+				{"type":"activate","model":"TestHeader"}
+				{"type":"message","message":"cli.testStatusMsg","data":true}
+				{"type":"result","command":"test","exit_code":2,"error":"cannot prompt in non-interactive mode, provide:\n  - service category (--category or SERVICE_CATEGORY)","output":"# Test Output\n","result":{"key":"value"},"missing":[{"name":"service category","flag":"--category","env":"SERVICE_CATEGORY"}]}
+			`),
+		},
+		{
+			name: "text",
+
+			output: "text",
+
+			want: "# Test Output\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := new(Config)
+			cfg.Output = test.output
+			ctx := ContextWithConfig(context.Background(), cfg)
+
+			var out bytes.Buffer
+			cmd := &cobra.Command{Use: "test"}
+			cmd.SetOut(&out)
+
+			err := runOutput(ctx, cmd, testRunTUI(test.err))
+			require.Equal(t, test.err, err)
+			require.Equal(t, test.want, out.String())
+		})
+	}
+}
+
+func TestRunCLIOutput(t *testing.T) {
+	cfg := new(Config)
+	cfg.Output = "json"
+	ctx := ContextWithConfig(context.Background(), cfg)
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{Use: "test"}
+
+	runCLI := func(ctx context.Context, streams Streams) error {
+		_, err := streams.Out.Write([]byte("plain output\n"))
+		return err
+	}
+	require.NoError(t, runCLIOutput(ctx, cmd, Streams{Out: &out}, runCLI))
+
+	require.Equal(t, `{"type":"result","command":"test","exit_code":0,"output":"plain output\n"}`+"\n", out.String())
+}
@@ -0,0 +1,48 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+)
+
+var CmdPlugin = cli.NewCmd[cli.ShowHelp](cli.CmdRoot, "plugin", func(cmd *cobra.Command) {})
+
+var CmdPluginList = cli.NewCmd[List](CmdPlugin, "list", func(cmd *cobra.Command) {})
+
+type List struct{}
+
+func (c List) UI() cli.UI {
+	return cli.UI{
+		RunCLI: c.runCLI,
+	}
+}
+
+func (c *List) runCLI(ctx context.Context, streams cli.Streams) error {
+	cfg := cli.ConfigFromContext(ctx)
+
+	plugins := Find(cli.CmdRoot, Dirs(cfg))
+	if plugins == nil {
+		plugins = []Plugin{}
+	}
+	cli.SetResult(ctx, plugins)
+
+	if len(plugins) == 0 {
+		_, err := fmt.Fprintln(streams.Out, "No plugins found.")
+		return err
+	}
+
+	w := tabwriter.NewWriter(streams.Out, 0, 4, 2, ' ', 0)
+	for _, plugin := range plugins {
+		if plugin.Shadowed {
+			fmt.Fprintf(w, "%s\t%s\t(shadowed)\n", plugin.Name, plugin.Path)
+		} else {
+			fmt.Fprintf(w, "%s\t%s\n", plugin.Name, plugin.Path)
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,236 @@
+// Package plugin runs external executables named anchor-<name> as anchor
+// subcommands.
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/keyring"
+	"github.com/anchordotdev/cli/ui"
+)
+
+// Prefix is the prefix of plugin executable names.
+const Prefix = "anchor-"
+
+type Plugin struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+
+	// Shadowed is set for plugins hidden by a built-in command or an earlier
+	// plugin of the same name.
+	Shadowed bool `json:"shadowed,omitempty"`
+}
+
+// Dirs returns the directories searched for plugins, in order.
+func Dirs(cfg *cli.Config) []string {
+	var dirs []string
+	if dir := cfg.PluginDir(); dir != "" {
+		dirs = append(dirs, dir)
+	}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir != "" && !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// Find returns the plugins in dirs. Plugins with the same name as a command of
+// root or an earlier plugin are marked shadowed.
+func Find(root *cobra.Command, dirs []string) []Plugin {
+	var (
+		plugins []Plugin
+		seen    = make(map[string]bool)
+	)
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue // missing or unreadable PATH entries are common
+		}
+
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+
+			plugins = append(plugins, Plugin{
+				Name:     name,
+				Path:     path,
+				Shadowed: seen[name] || isBuiltin(root, name),
+			})
+			seen[name] = true
+		}
+	}
+	return plugins
+}
+
+// Lookup returns the plugin run for name, if any.
+func Lookup(root *cobra.Command, dirs []string, name string) (Plugin, bool) {
+	for _, plugin := range Find(root, dirs) {
+		if plugin.Name == name && !plugin.Shadowed {
+			return plugin, true
+		}
+	}
+	return Plugin{}, false
+}
+
+// Dispatch runs the plugin named by the first of args when it is not a command
+// of root. It reports whether a plugin was run, and writes errors other than
+// the exit status of the plugin to stderr. Unknown commands that are not
+// plugins are left to root to report. A config that can not be loaded is only
+// returned as an error once a plugin is found, since it can't be run without.
+func Dispatch(ctx context.Context, root *cobra.Command, args []string) (bool, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return false, nil
+	}
+
+	// help and completion are added to root when it is executed
+	root.InitDefaultHelpCmd()
+	root.InitDefaultCompletionCmd()
+
+	if isBuiltin(root, args[0]) {
+		return false, nil
+	}
+
+	streams := cli.Streams{
+		In:  os.Stdin,
+		Out: os.Stdout,
+		Err: os.Stderr,
+	}
+
+	// the plugins directory is next to the user config file, found without
+	// loading any config
+	plugin, ok := Lookup(root, Dirs(new(cli.Config)), args[0])
+	if !ok {
+		return false, nil
+	}
+
+	cfg := new(cli.Config)
+	if err := cfg.Load(root.Context()); err != nil {
+		fmt.Fprintln(streams.Err, ui.Danger("Error!"), err)
+		return false, err
+	}
+
+	err := Run(ctx, cfg, plugin, args[1:], streams)
+	if err != nil && !errors.As(err, new(cli.ExitError)) {
+		fmt.Fprintln(streams.Err, ui.Danger("Error!"), err)
+	}
+	return true, err
+}
+
+// Run runs plugin with args and the config in its environment. A non-zero
+// exit status of the plugin is returned as an ExitError with the same code.
+func Run(ctx context.Context, cfg *cli.Config, plugin Plugin, args []string, streams cli.Streams) error {
+	cmd := exec.CommandContext(ctx, plugin.Path, args...)
+	cmd.Env = append(os.Environ(), Env(cfg, plugin)...)
+	cmd.Stdin = streams.In
+	cmd.Stdout = streams.Out
+	cmd.Stderr = streams.Err
+
+	err := cmd.Run()
+
+	var eerr *exec.ExitError
+	if errors.As(err, &eerr) {
+		return cli.ExitError{Code: eerr.ExitCode(), Err: fmt.Errorf("plugin %s: %w", plugin.Name, err)}
+	}
+	if err != nil {
+		return fmt.Errorf("running plugin %s: %w", plugin.Name, err)
+	}
+	return nil
+}
+
+// Env returns the environment variables passing the resolved config to plugin.
+// They are the variables the CLI reads, so that a plugin calling anchor sees the
+// same config. The API token is read from the keyring when it is not
+// configured, and omitted when signed out. It is only passed to plugins in the
+// plugins directory, unless plugin.path-token is set, since any executable on
+// PATH named anchor-<name> is run as a plugin.
+func Env(cfg *cli.Config, plugin Plugin) []string {
+	var token string
+	if cfg.Plugin.PathToken || inPluginDir(cfg, plugin) {
+		if token = cfg.API.Token; token == "" {
+			kr := keyring.Keyring{Config: cfg}
+			token, _ = kr.Get(keyring.APIToken)
+		}
+	}
+
+	realm := cfg.Realm.APID
+	if realm == "" {
+		realm = cfg.Lcl.RealmAPID
+	}
+
+	vars := []struct{ key, value string }{
+		{"API_URL", cfg.API.URL},
+		{"API_TOKEN", token},
+		{"ORG", cfg.Org.APID},
+		{"REALM", realm},
+		{"SERVICE", cfg.Service.APID},
+	}
+
+	var env []string
+	for _, v := range vars {
+		if v.value != "" {
+			env = append(env, v.key+"="+v.value)
+		}
+	}
+	return env
+}
+
+func inPluginDir(cfg *cli.Config, plugin Plugin) bool {
+	dir := cfg.PluginDir()
+	return dir != "" && filepath.Clean(filepath.Dir(plugin.Path)) == filepath.Clean(dir)
+}
+
+func isBuiltin(root *cobra.Command, name string) bool {
+	if name == cobra.ShellCompRequestCmd || name == cobra.ShellCompNoDescRequestCmd {
+		return true
+	}
+	cmd, _, err := root.Find([]string{name})
+	return err == nil && cmd != root
+}
+
+func pluginName(filename string) (string, bool) {
+	name, ok := strings.CutPrefix(filename, Prefix)
+	if !ok {
+		return "", false
+	}
+	if runtime.GOOS == "windows" {
+		ext := filepath.Ext(name)
+		if !slices.Contains(windowsExts, strings.ToLower(ext)) {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name, name != ""
+}
+
+var windowsExts = []string{".bat", ".cmd", ".com", ".exe"}
+
+func isExecutable(path string) bool {
+	fi, err := os.Stat(path)
+	if err != nil || fi.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true // checked by extension
+	}
+	return fi.Mode().Perm()&0111 != 0
+}
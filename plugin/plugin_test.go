@@ -0,0 +1,163 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/cmdtest"
+	_ "github.com/anchordotdev/cli/testflags"
+)
+
+func TestCmdPlugin(t *testing.T) {
+	t.Run("plugin", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdPlugin, "plugin")
+	})
+
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdPlugin, "plugin", "--help")
+	})
+}
+
+func TestCmdPluginList(t *testing.T) {
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdPluginList, "plugin", "list", "--help")
+	})
+}
+
+func writePlugin(t *testing.T, dir, name, script string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, Prefix+name)
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755))
+	return path
+}
+
+func TestFind(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are found by extension on windows")
+	}
+
+	first, second := t.TempDir(), t.TempDir()
+
+	syncPath := writePlugin(t, first, "vault-sync", "true")
+	writePlugin(t, first, "plugin", "true")
+	shadowedPath := writePlugin(t, second, "vault-sync", "true")
+	require.NoError(t, os.WriteFile(filepath.Join(second, Prefix+"readme"), nil, 0644))
+
+	want := []Plugin{
+		{Name: "plugin", Path: filepath.Join(first, Prefix+"plugin"), Shadowed: true},
+		{Name: "vault-sync", Path: syncPath},
+		{Name: "vault-sync", Path: shadowedPath, Shadowed: true},
+	}
+	require.Equal(t, want, Find(cli.CmdRoot, []string{first, second, filepath.Join(first, "missing")}))
+
+	plugin, ok := Lookup(cli.CmdRoot, []string{first, second}, "vault-sync")
+	require.True(t, ok)
+	require.Equal(t, syncPath, plugin.Path)
+
+	_, ok = Lookup(cli.CmdRoot, []string{first, second}, "plugin")
+	require.False(t, ok, "built-in commands take precedence over plugins")
+}
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugins are shell scripts")
+	}
+
+	cfg := cmdtest.Config(context.Background())
+	cfg.API.Token = "ap0_token"
+	cfg.Org.APID = "org-apid"
+	cfg.Lcl.RealmAPID = "realm-apid"
+	cfg.Test.UserTOML = filepath.Join(t.TempDir(), "config.toml")
+
+	dir := cfg.PluginDir()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+
+	script := `echo "$@ $API_TOKEN $ORG $REALM"; exit 3`
+	path := writePlugin(t, dir, "env", script)
+	pathOnPATH := writePlugin(t, t.TempDir(), "env", script)
+
+	t.Run("plugins directory", func(t *testing.T) {
+		var out bytes.Buffer
+		err := Run(context.Background(), cfg, Plugin{Name: "env", Path: path}, []string{"--flag", "arg"}, cli.Streams{Out: &out})
+
+		require.Equal(t, "--flag arg ap0_token org-apid realm-apid\n", out.String())
+		require.Equal(t, 3, cli.ExitCode(err))
+	})
+
+	t.Run("PATH", func(t *testing.T) {
+		var out bytes.Buffer
+		err := Run(context.Background(), cfg, Plugin{Name: "env", Path: pathOnPATH}, nil, cli.Streams{Out: &out})
+
+		require.Equal(t, "  org-apid realm-apid\n", out.String())
+		require.Equal(t, 3, cli.ExitCode(err))
+	})
+
+	t.Run("PATH with plugin.path-token", func(t *testing.T) {
+		cfg := *cfg
+		cfg.Plugin.PathToken = true
+
+		var out bytes.Buffer
+		err := Run(context.Background(), &cfg, Plugin{Name: "env", Path: pathOnPATH}, nil, cli.Streams{Out: &out})
+
+		require.Equal(t, " ap0_token org-apid realm-apid\n", out.String())
+		require.Equal(t, 3, cli.ExitCode(err))
+	})
+}
+
+func TestDispatchConfigError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugins are shell scripts")
+	}
+
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.WriteFile("anchor.toml", []byte("[org\n"), 0644))
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	t.Setenv("PATH", dir)
+	writePlugin(t, dir, "vault-sync", "true")
+
+	t.Run("unknown command", func(t *testing.T) {
+		ok, err := Dispatch(context.Background(), cli.CmdRoot, []string{"lcll"})
+		require.False(t, ok)
+		require.NoError(t, err, "unknown commands are reported by cobra")
+	})
+
+	t.Run("plugin", func(t *testing.T) {
+		ok, err := Dispatch(context.Background(), cli.CmdRoot, []string{"vault-sync"})
+		require.False(t, ok)
+		require.Error(t, err)
+	})
+}
+
+func TestList(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are found by extension on windows")
+	}
+
+	cfg := cmdtest.Config(context.Background())
+	cfg.Test.UserTOML = filepath.Join(t.TempDir(), "config.toml")
+	t.Setenv("PATH", "")
+
+	dir := cfg.PluginDir()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	writePlugin(t, dir, "vault-sync", "true")
+	writePlugin(t, dir, "plugin", "true")
+
+	var out bytes.Buffer
+	cmd := List{}
+	require.NoError(t, cmd.UI().RunCLI(cli.ContextWithConfig(context.Background(), cfg), cli.Streams{Out: &out}))
+
+	want := "" +
+		"plugin      " + filepath.Join(dir, "anchor-plugin") + "  (shadowed)\n" +
+		"vault-sync  " + filepath.Join(dir, "anchor-vault-sync") + "\n"
+	require.Equal(t, want, out.String())
+}
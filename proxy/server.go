@@ -0,0 +1,92 @@
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync/atomic"
+
+	"github.com/anchordotdev/cli/diagnostic"
+)
+
+// Server terminates TLS for requests to its address and forwards them to the
+// target, along with plain HTTP requests on the same address. HTTP/1.1, HTTP/2
+// and WebSocket requests are forwarded.
+type Server struct {
+	Addr string
+
+	// Target is the URL requests are forwarded to, such as a development
+	// server at http://localhost:3000.
+	Target *url.URL
+
+	// RedirectHTTP redirects plain HTTP requests to HTTPS, instead of
+	// forwarding them.
+	RedirectHTTP bool
+
+	GetCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)
+
+	sni diagnostic.SNIServer
+
+	requests atomic.Uint64
+}
+
+func (s *Server) Start(ctx context.Context) error {
+	rp := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(s.Target)
+			r.SetXForwarded()
+		},
+		ErrorHandler: s.serveError,
+		ErrorLog:     log.New(io.Discard, "", 0),
+	}
+
+	s.sni = diagnostic.SNIServer{
+		Addr: s.Addr,
+
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS == nil && s.RedirectHTTP {
+				s.redirect(w, r)
+				return
+			}
+
+			s.requests.Add(1)
+			rp.ServeHTTP(w, r)
+		}),
+
+		GetCertificate: s.GetCertificate,
+	}
+	if err := s.sni.Start(ctx); err != nil {
+		return err
+	}
+	s.Addr = s.sni.Addr
+
+	return nil
+}
+
+func (s *Server) Close() error {
+	return s.sni.Close()
+}
+
+// Requests returns the number of requests forwarded to the target.
+func (s *Server) Requests() uint64 {
+	return s.requests.Load()
+}
+
+func (s *Server) redirect(w http.ResponseWriter, r *http.Request) {
+	u := *r.URL
+	u.Scheme = "https"
+	u.Host = r.Host
+
+	http.Redirect(w, r, u.String(), http.StatusPermanentRedirect)
+}
+
+func (s *Server) serveError(w http.ResponseWriter, r *http.Request, err error) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusBadGateway)
+
+	_, _ = io.WriteString(w, "anchor lcl proxy: "+s.Target.String()+" is unavailable, is your application running?\n\n"+err.Error()+"\n")
+}
@@ -0,0 +1,248 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/anchordotdev/cli/internal/must"
+	_ "github.com/anchordotdev/cli/testflags"
+)
+
+func TestServerForwardsRequests(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test-Host", r.Host)
+		w.Header().Set("X-Test-Forwarded-Host", r.Header.Get("X-Forwarded-Host"))
+		w.Header().Set("X-Test-Forwarded-Proto", r.Header.Get("X-Forwarded-Proto"))
+		w.Header().Set("X-Test-Forwarded-For", r.Header.Get("X-Forwarded-For"))
+		_, _ = io.WriteString(w, r.URL.Path)
+	}))
+	defer target.Close()
+
+	srv, port := startServer(t, target.URL, false)
+
+	client := testClient(srv)
+
+	t.Run("https", func(t *testing.T) {
+		res, err := client.Get("https://example.lcl.host.test:" + port + "/hello")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+
+		if want, got := "HTTP/2.0", res.Proto; want != got {
+			t.Errorf("want proto %s, got %s", want, got)
+		}
+		if body, _ := io.ReadAll(res.Body); string(body) != "/hello" {
+			t.Errorf("want body %q, got %q", "/hello", body)
+		}
+
+		targetURL, _ := url.Parse(target.URL)
+		for header, want := range map[string]string{
+			"X-Test-Host":            targetURL.Host,
+			"X-Test-Forwarded-Host":  "example.lcl.host.test:" + port,
+			"X-Test-Forwarded-Proto": "https",
+			"X-Test-Forwarded-For":   "127.0.0.1",
+		} {
+			if got := res.Header.Get(header); want != got {
+				t.Errorf("want %s %q, got %q", header, want, got)
+			}
+		}
+	})
+
+	t.Run("http", func(t *testing.T) {
+		res, err := client.Get("http://example.lcl.host.test:" + port + "/hello")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+
+		if want, got := http.StatusOK, res.StatusCode; want != got {
+			t.Errorf("want status %d, got %d", want, got)
+		}
+		if want, got := "http", res.Header.Get("X-Test-Forwarded-Proto"); want != got {
+			t.Errorf("want X-Forwarded-Proto %q, got %q", want, got)
+		}
+	})
+
+	if want, got := uint64(2), srv.Requests(); want != got {
+		t.Errorf("want %d requests, got %d", want, got)
+	}
+}
+
+func TestServerRedirectsHTTP(t *testing.T) {
+	srv, port := startServer(t, "http://127.0.0.1:1", true)
+
+	client := testClient(srv)
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	res, err := client.Get("http://example.lcl.host.test:" + port + "/path?q=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	if want, got := http.StatusPermanentRedirect, res.StatusCode; want != got {
+		t.Errorf("want status %d, got %d", want, got)
+	}
+	if want, got := "https://example.lcl.host.test:"+port+"/path?q=1", res.Header.Get("Location"); want != got {
+		t.Errorf("want location %q, got %q", want, got)
+	}
+}
+
+func TestServerUnavailableTarget(t *testing.T) {
+	srv, port := startServer(t, "http://127.0.0.1:1", false)
+
+	res, err := testClient(srv).Get("https://example.lcl.host.test:" + port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	if want, got := http.StatusBadGateway, res.StatusCode; want != got {
+		t.Errorf("want status %d, got %d", want, got)
+	}
+}
+
+func TestServerForwardsWebSockets(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" {
+			http.Error(w, "upgrade required", http.StatusUpgradeRequired)
+			return
+		}
+
+		conn, rw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+		_ = rw.Flush()
+
+		// echo
+		_, _ = io.Copy(conn, rw)
+	}))
+	defer target.Close()
+
+	srv, _ := startServer(t, target.URL, false)
+
+	conn, err := tls.Dial("tcp", srv.Addr, &tls.Config{
+		ServerName: "example.lcl.host.test",
+		RootCAs:    anchorCA.CertPool(),
+		NextProtos: []string{"http/1.1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	_, err = io.WriteString(conn, "GET /ws HTTP/1.1\r\nHost: example.lcl.host.test\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	br := bufio.NewReader(conn)
+	res, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := http.StatusSwitchingProtocols, res.StatusCode; want != got {
+		t.Fatalf("want status %d, got %d", want, got)
+	}
+
+	if _, err := io.WriteString(conn, "ping"); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(br, buf); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "ping", string(buf); want != got {
+		t.Errorf("want echo %q, got %q", want, got)
+	}
+}
+
+func startServer(t *testing.T, target string, redirectHTTP bool) (*Server, string) {
+	t.Helper()
+
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert := leaf.TLS()
+
+	srv := &Server{
+		Addr:         "127.0.0.1:0",
+		Target:       targetURL,
+		RedirectHTTP: redirectHTTP,
+		GetCertificate: func(cii *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return &cert, nil
+		},
+	}
+
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = srv.Close() })
+
+	_, port, err := net.SplitHostPort(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv, port
+}
+
+func testClient(srv *Server) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			ForceAttemptHTTP2: true,
+			DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return new(net.Dialer).DialContext(ctx, network, srv.Addr)
+			},
+			TLSClientConfig: &tls.Config{
+				RootCAs: anchorCA.CertPool(),
+			},
+		},
+	}
+}
+
+var (
+	anchorCA = must.CA(&x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:   "Example CA - AnchorCA",
+			Organization: []string{"Example, Inc"},
+		},
+		KeyUsage: x509.KeyUsageCertSign,
+		IsCA:     true,
+	})
+
+	subCA = anchorCA.Issue(&x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:   "Example CA - SubCA",
+			Organization: []string{"Example, Inc"},
+		},
+		KeyUsage:              x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	})
+
+	leaf = subCA.Issue(&x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:   "example.lcl.host.test",
+			Organization: []string{"Example, Inc"},
+		},
+
+		DNSNames: []string{"example.lcl.host.test", "*.example.lcl.host.test"},
+	})
+)
@@ -7,12 +7,20 @@ import (
 var CmdRoot = NewCmd[ShowHelp](nil, "anchor", func(cmd *cobra.Command) {
 	cfg := ConfigFromCmd(cmd)
 
+	cmd.PersistentFlags().StringVar(&cfg.Answers, "answers", Defaults.Answers, "Answer prompts from a TOML file instead of asking, failing on unanswered prompts.")
 	cmd.PersistentFlags().StringVar(&cfg.API.Token, "api-token", Defaults.API.Token, "Anchor API personal access token (PAT).")
 	cmd.PersistentFlags().StringVar(&cfg.API.URL, "api-url", Defaults.API.URL, "Anchor API endpoint URL.")
 	cmd.PersistentFlags().StringVar(&cfg.File.Path, "config", Defaults.File.Path, "Service configuration file.")
 	cmd.PersistentFlags().StringVar(&cfg.Dashboard.URL, "dashboard-url", Defaults.Dashboard.URL, "Anchor dashboard URL.")
+	cmd.PersistentFlags().StringVar(&cfg.LogFile, "log-file", Defaults.LogFile, "Append a JSON log of the command's steps, API requests and trust store changes to file.")
+	cmd.PersistentFlags().StringVar(&cfg.Output, "output", Defaults.Output, "Output format, either json or text, instead of the interactive UI.")
+	cmd.PersistentFlags().StringVar(&cfg.Record, "record", Defaults.Record, "Record the interactive UI to file as an asciicast, for playback with anchor debug replay.")
 	cmd.PersistentFlags().BoolVar(&cfg.File.Skip, "skip-config", Defaults.File.Skip, "Skip loading configuration file.")
 
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return UserError{Err: err}
+	})
+
 	if err := cmd.PersistentFlags().MarkHidden("api-url"); err != nil {
 		panic(err)
 	}
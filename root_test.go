@@ -7,7 +7,10 @@ import (
 	"github.com/anchordotdev/cli"
 	_ "github.com/anchordotdev/cli/auth"
 	"github.com/anchordotdev/cli/cmdtest"
+	_ "github.com/anchordotdev/cli/config"
+	_ "github.com/anchordotdev/cli/debug"
 	_ "github.com/anchordotdev/cli/lcl"
+	_ "github.com/anchordotdev/cli/plugin"
 	_ "github.com/anchordotdev/cli/service"
 	_ "github.com/anchordotdev/cli/testflags"
 	_ "github.com/anchordotdev/cli/trust"
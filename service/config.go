@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/api"
+	"github.com/anchordotdev/cli/auth"
+	"github.com/anchordotdev/cli/cert"
+	"github.com/anchordotdev/cli/component"
+	componentmodels "github.com/anchordotdev/cli/component/models"
+	"github.com/anchordotdev/cli/integration"
+	"github.com/anchordotdev/cli/service/models"
+	"github.com/anchordotdev/cli/ui"
+)
+
+var CmdServiceConfig = cli.NewCmd[ServerConfig](CmdService, "config", func(cmd *cobra.Command) {
+	cfg := cli.ConfigFromCmd(cmd)
+
+	cmd.Flags().StringVarP(&cfg.Org.APID, "org", "o", cli.Defaults.Org.APID, "Organization of the service.")
+	cmd.Flags().StringVarP(&cfg.Realm.APID, "realm", "r", cli.Defaults.Realm.APID, "Realm of the service.")
+	cmd.Flags().StringVarP(&cfg.Service.APID, "service", "s", cli.Defaults.Service.APID, "Service to generate config for.")
+	cmd.Flags().StringVar(&cfg.Service.Server, "server", cli.Defaults.Service.Server, "Web server to generate config for: apache, caddy or nginx.")
+})
+
+type ServerConfig struct {
+	Anc *api.Session
+
+	ChainAPID, OrgAPID, RealmAPID, ServiceAPID string
+}
+
+func (c ServerConfig) UI() cli.UI {
+	return cli.UI{
+		RunTUI: c.run,
+	}
+}
+
+func (c *ServerConfig) run(ctx context.Context, drv *ui.Driver) error {
+	var err error
+	clientCmd := &auth.Client{
+		Anc: c.Anc,
+	}
+	c.Anc, err = clientCmd.Perform(ctx, drv)
+	if err != nil {
+		return err
+	}
+
+	drv.Activate(ctx, models.ServiceConfigHeader)
+	drv.Activate(ctx, models.ServiceConfigHint)
+
+	return c.Perform(ctx, drv)
+}
+
+type serverConfigResult struct {
+	Server string `json:"server"`
+	File   string `json:"file"`
+	Config string `json:"config"`
+}
+
+func (c *ServerConfig) Perform(ctx context.Context, drv *ui.Driver) error {
+	cfg := cli.ConfigFromContext(ctx)
+
+	chainAPID := c.ChainAPID
+	if chainAPID == "" {
+		chainAPID = "ca"
+	}
+
+	orgAPID, err := c.orgAPID(ctx, cfg, drv)
+	if err != nil {
+		return err
+	}
+
+	realmAPID, err := c.realmAPID(ctx, cfg, drv, orgAPID)
+	if err != nil {
+		return err
+	}
+
+	serviceAPID, err := c.serviceAPID(ctx, cfg, drv, orgAPID, realmAPID)
+	if err != nil {
+		return err
+	}
+
+	service, err := c.Anc.GetService(ctx, orgAPID, serviceAPID)
+	if err != nil {
+		return err
+	}
+
+	server := cfg.Service.Server
+	if server == "" && slices.Contains(integration.Servers, string(service.ServerType)) {
+		server = string(service.ServerType)
+	}
+	if server == "" {
+		return cli.UserError{
+			Err: fmt.Errorf("%s is a %s service, use --server to choose one of: %s", serviceAPID, service.ServerType, strings.Join(integration.Servers, ", ")),
+		}
+	}
+
+	attachments, err := c.Anc.GetServiceAttachments(ctx, orgAPID, serviceAPID)
+	if err != nil {
+		return err
+	}
+	idx := slices.IndexFunc(attachments, func(a api.Attachment) bool {
+		return a.Relationships.Chain.Apid == chainAPID && a.Relationships.Realm.Apid == realmAPID && len(a.Domains) > 0
+	})
+	if idx == -1 {
+		return cli.UserError{
+			Err: errors.New("the service has no domains in this realm, run `anchor lcl setup` to add them"),
+		}
+	}
+	domains := attachments[idx].Domains
+
+	// the config references the certificate files by absolute path, since web
+	// servers resolve relative paths from their own directory
+	_, chainFile, keyFile := cert.Filenames(domains[0], domains)
+	if chainFile, err = filepath.Abs(chainFile); err != nil {
+		return err
+	}
+	if keyFile, err = filepath.Abs(keyFile); err != nil {
+		return err
+	}
+
+	in, err := integration.ServerConfig(server, integration.Params{
+		ServerNames:      domains,
+		Port:             *service.LocalhostPort,
+		ACMEDirectoryURL: cfg.AcmeURL(orgAPID, realmAPID, chainAPID),
+		CertFile:         chainFile,
+		KeyFile:          keyFile,
+	})
+	if err != nil {
+		return cli.UserError{Err: err}
+	}
+	config := in.Files[0]
+
+	cli.SetResult(ctx, serverConfigResult{
+		Server: server,
+		File:   config.Path,
+		Config: string(config.New),
+	})
+
+	drv.Activate(ctx, &models.ConfigDisplay{
+		Server:  in.Name,
+		Config:  string(config.New),
+		Service: serviceAPID,
+	})
+
+	steps := append([]string{
+		fmt.Sprintf("Save this config as %s.", config.Path),
+	}, in.Steps...)
+	if in.CertFiles {
+		steps = append(steps, fmt.Sprintf("Run `anchor lcl mkcert --domains %s --org %s --realm %s --service %s` to write the certificate files.",
+			strings.Join(domains, ","),
+			orgAPID,
+			realmAPID,
+			serviceAPID,
+		))
+	}
+
+	drv.Activate(ctx, &models.ConfigNextSteps{
+		Steps:  steps,
+		LclUrl: fmt.Sprintf("https://%s:%d", domains[0], *service.LocalhostPort),
+	})
+
+	return nil
+}
+
+func (c *ServerConfig) orgAPID(ctx context.Context, cfg *cli.Config, drv *ui.Driver) (string, error) {
+	if c.OrgAPID != "" {
+		return c.OrgAPID, nil
+	}
+	if cfg.Org.APID != "" {
+		drv.Activate(ctx, &componentmodels.ConfigVia{
+			Config:        cfg,
+			ConfigFetchFn: func(cfg *cli.Config) any { return cfg.Org.APID },
+			Flag:          "--org",
+			Singular:      "organization",
+		})
+		c.OrgAPID = cfg.Org.APID
+		return c.OrgAPID, nil
+	}
+
+	selector := &component.Selector[api.Organization]{
+		Prompt: "Which organization's service do you want to configure?",
+		Flag:   "--org",
+
+		Fetcher: &component.Fetcher[api.Organization]{
+			FetchFn: func() ([]api.Organization, error) { return c.Anc.GetOrgs(ctx) },
+		},
+	}
+
+	org, err := selector.Choice(ctx, drv)
+	if err != nil {
+		return "", err
+	}
+	return org.Apid, nil
+}
+
+func (c *ServerConfig) realmAPID(ctx context.Context, cfg *cli.Config, drv *ui.Driver, orgAPID string) (string, error) {
+	if c.RealmAPID != "" {
+		return c.RealmAPID, nil
+	}
+	if cfg.Realm.APID != "" {
+		drv.Activate(ctx, &componentmodels.ConfigVia{
+			Config:        cfg,
+			ConfigFetchFn: func(cfg *cli.Config) any { return cfg.Realm.APID },
+			Flag:          "--realm",
+			Singular:      "realm",
+		})
+		c.RealmAPID = cfg.Realm.APID
+		return c.RealmAPID, nil
+	}
+	if cfg.Lcl.RealmAPID != "" {
+		drv.Activate(ctx, &componentmodels.ConfigVia{
+			Config:        cfg,
+			ConfigFetchFn: func(cfg *cli.Config) any { return cfg.Lcl.RealmAPID },
+			Flag:          "--realm",
+			Singular:      "realm",
+		})
+		c.RealmAPID = cfg.Lcl.RealmAPID
+		return c.RealmAPID, nil
+	}
+
+	selector := &component.Selector[api.Realm]{
+		Prompt: fmt.Sprintf("Which %s realm's service do you want to configure?", ui.Emphasize(orgAPID)),
+		Flag:   "--realm",
+
+		Fetcher: &component.Fetcher[api.Realm]{
+			FetchFn: func() ([]api.Realm, error) { return c.Anc.GetOrgRealms(ctx, orgAPID) },
+		},
+	}
+
+	realm, err := selector.Choice(ctx, drv)
+	if err != nil {
+		return "", err
+	}
+	return realm.Apid, nil
+}
+
+func (c *ServerConfig) serviceAPID(ctx context.Context, cfg *cli.Config, drv *ui.Driver, orgAPID, realmAPID string) (string, error) {
+	if c.ServiceAPID != "" {
+		return c.ServiceAPID, nil
+	}
+	if cfg.Service.APID != "" {
+		drv.Activate(ctx, &componentmodels.ConfigVia{
+			Config:        cfg,
+			ConfigFetchFn: func(cfg *cli.Config) any { return cfg.Service.APID },
+			Flag:          "--service",
+			Singular:      "service",
+		})
+		c.ServiceAPID = cfg.Service.APID
+		return c.ServiceAPID, nil
+	}
+
+	selector := &component.Selector[api.Service]{
+		Prompt: fmt.Sprintf("Which %s/%s service do you want to configure?", ui.Emphasize(orgAPID), ui.Emphasize(realmAPID)),
+		Flag:   "--service",
+
+		Fetcher: &component.Fetcher[api.Service]{
+			FetchFn: func() ([]api.Service, error) { return c.Anc.GetOrgServices(ctx, orgAPID, api.NonDiagnosticServices) },
+		},
+	}
+
+	service, err := selector.Choice(ctx, drv)
+	if err != nil {
+		return "", err
+	}
+	return service.Slug, nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/cmdtest"
+	"github.com/anchordotdev/cli/ui/uitest"
+)
+
+func TestCmdServiceConfig(t *testing.T) {
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdServiceConfig, "service", "config", "--help")
+	})
+
+	t.Run("--server nginx", func(t *testing.T) {
+		cfg := cmdtest.TestCfg(t, CmdServiceConfig, "--server", "nginx")
+		require.Equal(t, "nginx", cfg.Service.Server)
+	})
+
+	t.Run("SERVICE_SERVER=caddy", func(t *testing.T) {
+		t.Setenv("SERVICE_SERVER", "caddy")
+
+		cfg := cmdtest.TestCfg(t, CmdServiceConfig)
+		require.Equal(t, "caddy", cfg.Service.Server)
+	})
+
+	t.Run("--service testService", func(t *testing.T) {
+		cfg := cmdtest.TestCfg(t, CmdServiceConfig, "--service", "testService")
+		require.Equal(t, "testService", cfg.Service.APID)
+	})
+}
+
+func TestServerConfigRealmAPID(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := new(cli.Config)
+	cfg.Lcl.RealmAPID = "localhost"
+	ctx = cli.ContextWithConfig(ctx, cfg)
+
+	drv, tm := uitest.TestTUI(ctx, t)
+	defer tm.Quit()
+
+	cmd := ServerConfig{}
+	realmAPID, err := cmd.realmAPID(ctx, cfg, drv, "org-apid")
+	require.NoError(t, err)
+	require.Equal(t, "localhost", realmAPID)
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/api"
+	"github.com/anchordotdev/cli/ui"
+)
+
+var (
+	// CmdCredentialHelper runs are made by other programs, often many times
+	// over, so they are not recorded as the last command.
+	CmdCredentialHelper = cli.NewCmd[cli.ShowHelp](cli.CmdRoot, "credential-helper", func(cmd *cobra.Command) {
+		cmd.Annotations = map[string]string{cli.SkipLastCommandLog: ""}
+	})
+
+	CmdCredentialHelperGet = cli.NewCmd[CredentialHelperGet](CmdCredentialHelper, "get", func(cmd *cobra.Command) {})
+)
+
+type CredentialRequest struct {
+	Org     string `json:"org"`
+	Realm   string `json:"realm"`
+	Service string `json:"service"`
+	Chain   string `json:"chain,omitempty"`
+}
+
+type CredentialResponse struct {
+	AcmeDirectoryURL string `json:"ACME_DIRECTORY_URL"`
+	AcmeKID          string `json:"ACME_KID"`
+	AcmeHMACKey      string `json:"ACME_HMAC_KEY"`
+	AcmeContact      string `json:"ACME_CONTACT"`
+}
+
+type CredentialHelperGet struct {
+	Anc *api.Session
+}
+
+func (c CredentialHelperGet) UI() cli.UI {
+	return cli.UI{
+		RunCLI: c.runCLI,
+	}
+}
+
+func (c *CredentialHelperGet) runCLI(ctx context.Context, streams cli.Streams) error {
+	cfg := cli.ConfigFromContext(ctx)
+
+	cli.SkipReleaseCheck = true
+
+	var req CredentialRequest
+	if err := json.NewDecoder(streams.In).Decode(&req); err != nil {
+		return cli.ExitError{
+			Code: cli.ExitCodeCredentialHelperInvalidRequest,
+			Err:  fmt.Errorf("invalid credential request: %w", err),
+		}
+	}
+
+	res, err := c.Get(ctx, cfg, req)
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(streams.Out).Encode(res)
+}
+
+func (c *CredentialHelperGet) Get(ctx context.Context, cfg *cli.Config, req CredentialRequest) (*CredentialResponse, error) {
+	if req.Org == "" {
+		req.Org = cfg.Org.APID
+	}
+	if req.Realm == "" {
+		req.Realm = cfg.Realm.APID
+	}
+	if req.Service == "" {
+		req.Service = cfg.Service.APID
+	}
+	if req.Org == "" || req.Realm == "" || req.Service == "" {
+		return nil, cli.ExitError{
+			Code: cli.ExitCodeCredentialHelperInvalidRequest,
+			Err:  errors.New("invalid credential request: org, realm and service are required"),
+		}
+	}
+
+	if c.Anc == nil {
+		var err error
+		if c.Anc, err = api.NewClient(ctx, cfg); err != nil {
+			return nil, credentialHelperError(err)
+		}
+	}
+
+	env := &Env{
+		Anc:         c.Anc,
+		ChainAPID:   req.Chain,
+		OrgAPID:     req.Org,
+		RealmAPID:   req.Realm,
+		ServiceAPID: req.Service,
+	}
+
+	vars, attachment, _, err := env.fetchEnv(ctx, cfg, env.chainAPID(), req.Org, req.Realm, req.Service)
+	if err != nil {
+		return nil, credentialHelperError(err)
+	}
+	if attachment == nil {
+		return nil, cli.ExitError{
+			Code: cli.ExitCodeCredentialHelperNotFound,
+			Err:  fmt.Errorf("no %s/%s attachment found for service %s", req.Realm, env.chainAPID(), req.Service),
+		}
+	}
+
+	return &CredentialResponse{
+		AcmeDirectoryURL: vars["ACME_DIRECTORY_URL"],
+		AcmeKID:          vars["ACME_KID"],
+		AcmeHMACKey:      vars["ACME_HMAC_KEY"],
+		AcmeContact:      vars["ACME_CONTACT"],
+	}, nil
+}
+
+func credentialHelperError(err error) error {
+	var uierr ui.Error
+	switch {
+	case errors.As(err, &uierr) && uierr.Err == nil:
+		return cli.ExitError{Code: cli.ExitCodeError, Err: errors.New("this version of the Anchor CLI is out-of-date, please update")}
+	case errors.Is(err, api.ErrSignedOut):
+		return cli.ExitError{Code: cli.ExitCodeCredentialHelperSignedOut, Err: err}
+	case errors.Is(err, api.NotFoundErr):
+		return cli.ExitError{Code: cli.ExitCodeCredentialHelperNotFound, Err: err}
+	default:
+		return cli.ExitError{Code: cli.ExitCodeError, Err: err}
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/cmdtest"
+)
+
+func TestCmdCredentialHelper(t *testing.T) {
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdCredentialHelper, "credential-helper", "--help")
+	})
+}
+
+func TestCmdCredentialHelperGet(t *testing.T) {
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdCredentialHelperGet, "credential-helper", "get", "--help")
+	})
+}
+
+func TestCredentialHelperGet(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := new(cli.Config)
+	cfg.Dashboard.URL = "http://anchor.lcl.host"
+	cfg.API.URL = srv.URL
+	var err error
+	if cfg.API.Token, err = srv.GeneratePAT("anky@anchor.dev"); err != nil {
+		t.Fatal(err)
+	}
+	ctx = cli.ContextWithConfig(ctx, cfg)
+
+	t.Run("invalid-json", func(t *testing.T) {
+		var out bytes.Buffer
+
+		cmd := CredentialHelperGet{}
+		err := cmd.UI().RunCLI(ctx, cli.Streams{In: strings.NewReader("nope"), Out: &out, Err: &out})
+
+		require.Equal(t, cli.ExitCodeCredentialHelperInvalidRequest, cli.ExitCode(err))
+		require.Empty(t, out.String())
+	})
+
+	t.Run("missing-service", func(t *testing.T) {
+		var out bytes.Buffer
+
+		cmd := CredentialHelperGet{}
+		err := cmd.UI().RunCLI(ctx, cli.Streams{In: strings.NewReader(`{"org":"org","realm":"realm"}`), Out: &out, Err: &out})
+
+		require.Equal(t, cli.ExitCodeCredentialHelperInvalidRequest, cli.ExitCode(err))
+	})
+
+	t.Run("basics", func(t *testing.T) {
+		if srv.IsProxy() {
+			t.Skip("credential helper unsupported in proxy mode")
+		}
+
+		var out bytes.Buffer
+
+		cmd := CredentialHelperGet{}
+		err := cmd.UI().RunCLI(ctx, cli.Streams{In: strings.NewReader(`{"org":"org-slug","realm":"realm-slug","service":"service-name"}`), Out: &out, Err: &out})
+		require.NoError(t, err)
+
+		var res CredentialResponse
+		require.NoError(t, json.Unmarshal(out.Bytes(), &res))
+		require.Equal(t, "anky@anchor.dev", res.AcmeContact)
+		require.Equal(t, "http://anchor.lcl.host/org-slug/realm-slug/x509/ca/acme", res.AcmeDirectoryURL)
+		require.NotEmpty(t, res.AcmeKID)
+		require.NotEmpty(t, res.AcmeHMACKey)
+	})
+}
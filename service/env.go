@@ -99,48 +99,15 @@ func (c *Env) Perform(ctx context.Context, drv *ui.Driver) error {
 		return err
 	}
 
-	if c.whoami == "" {
-		userInfo, err := c.Anc.UserInfo(ctx)
-		if err != nil {
-			return err
-		}
-		c.whoami = userInfo.Whoami
-	}
-
 	drv.Activate(ctx, &models.EnvFetch{
 		Service: serviceAPID,
 	})
 
-	env := make(map[string]string)
-
-	env["ACME_CONTACT"] = c.whoami
-	env["ACME_DIRECTORY_URL"] = cfg.AcmeURL(orgAPID, realmAPID, chainAPID)
-
-	attachments, err := c.Anc.GetServiceAttachments(ctx, orgAPID, serviceAPID)
+	env, attachment, service, err := c.fetchEnv(ctx, cfg, chainAPID, orgAPID, realmAPID, serviceAPID)
 	if err != nil {
 		return err
 	}
-	var attachment *api.Attachment
-	for _, a := range attachments {
-		if a.Relationships.Chain.Apid == chainAPID && a.Relationships.Realm.Apid == realmAPID && a.Relationships.SubCa.Apid != nil {
-			attachment = &a
-			break
-		}
-	}
-
-	if attachment != nil {
-		eab, err := c.Anc.CreateEAB(ctx, chainAPID, orgAPID, realmAPID, serviceAPID, *attachment.Relationships.SubCa.Apid)
-		if err != nil {
-			return err
-		}
-		env["ACME_KID"] = eab.Kid
-		env["ACME_HMAC_KEY"] = eab.HmacKey
-	}
 
-	service, err := c.Anc.GetService(ctx, orgAPID, serviceAPID)
-	if err != nil {
-		return err
-	}
 	env["HTTPS_PORT"] = fmt.Sprintf("%d", *service.LocalhostPort)
 
 	if attachment != nil {
@@ -149,17 +116,31 @@ func (c *Env) Perform(ctx context.Context, drv *ui.Driver) error {
 
 	drv.Send(models.EnvFetchedMsg{})
 
+	cli.SetResult(ctx, env)
+
 	envOutput := cfg.Service.EnvOutput
+	if envOutput == "" && cfg.Output != "" {
+		envOutput = MethodDisplay // no prompt, the env is in the output
+	}
 	if envOutput == "" {
-		choicec := make(chan string)
-		drv.Activate(ctx, &models.EnvMethod{
-			ChoiceCh: choicec,
-		})
-
-		select {
-		case envOutput = <-choicec:
-		case <-ctx.Done():
-			return ctx.Err()
+		input := cli.Input{Name: "env output", Flag: "--env-output", Env: "ENV_OUTPUT", ID: cli.AnswerEnvOutput}
+		answer, ok, err := cli.Answer(ctx, input)
+		if err != nil {
+			return err
+		}
+		envOutput = answer
+
+		if !ok {
+			choicec := make(chan string)
+			drv.Activate(ctx, &models.EnvMethod{
+				ChoiceCh: choicec,
+			})
+
+			select {
+			case envOutput = <-choicec:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
 		}
 	}
 
@@ -205,6 +186,49 @@ func (c *Env) Perform(ctx context.Context, drv *ui.Driver) error {
 	return nil
 }
 
+func (c *Env) fetchEnv(ctx context.Context, cfg *cli.Config, chainAPID, orgAPID, realmAPID, serviceAPID string) (map[string]string, *api.Attachment, *api.Service, error) {
+	if c.whoami == "" {
+		userInfo, err := c.Anc.UserInfo(ctx)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		c.whoami = userInfo.Whoami
+	}
+
+	env := make(map[string]string)
+
+	env["ACME_CONTACT"] = c.whoami
+	env["ACME_DIRECTORY_URL"] = cfg.AcmeURL(orgAPID, realmAPID, chainAPID)
+
+	attachments, err := c.Anc.GetServiceAttachments(ctx, orgAPID, serviceAPID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var attachment *api.Attachment
+	for _, a := range attachments {
+		if a.Relationships.Chain.Apid == chainAPID && a.Relationships.Realm.Apid == realmAPID && a.Relationships.SubCa.Apid != nil {
+			attachment = &a
+			break
+		}
+	}
+
+	if attachment != nil {
+		eab, err := c.Anc.CreateEAB(ctx, chainAPID, orgAPID, realmAPID, serviceAPID, *attachment.Relationships.SubCa.Apid)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		env["ACME_KID"] = eab.Kid
+		env["ACME_HMAC_KEY"] = eab.HmacKey
+	}
+
+	service, err := c.Anc.GetService(ctx, orgAPID, serviceAPID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return env, attachment, service, nil
+}
+
 func (c *Env) exportMethod(ctx context.Context, cfg *cli.Config, drv *ui.Driver, env map[string]string, serviceAPID string) error {
 	var b strings.Builder
 
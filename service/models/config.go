@@ -0,0 +1,72 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/anchordotdev/cli/ui"
+)
+
+var (
+	ServiceConfigHeader = ui.Section{
+		Name: "ServiceConfigHeader",
+		Model: ui.MessageLines{
+			ui.Header(fmt.Sprintf("Generate Web Server Config for Service %s", ui.Whisper("`anchor service config`"))),
+		},
+	}
+
+	ServiceConfigHint = ui.Section{
+		Name: "ServiceConfigHint",
+		Model: ui.MessageLines{
+			ui.StepHint("We'll generate the TLS config for serving your service with your web server."),
+		},
+	}
+)
+
+type ConfigDisplay struct {
+	Server  string
+	Config  string
+	Service string
+}
+
+func (m *ConfigDisplay) Init() tea.Cmd { return nil }
+
+func (m *ConfigDisplay) Update(msg tea.Msg) (tea.Model, tea.Cmd) { return m, nil }
+
+func (m *ConfigDisplay) View() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "\n%s\n", m.Config)
+
+	fmt.Fprintln(&b, ui.StepDone(fmt.Sprintf(
+		"Displayed %s config for %s.",
+		ui.Emphasize(m.Server),
+		ui.Emphasize(m.Service))))
+
+	return b.String()
+}
+
+type ConfigNextSteps struct {
+	Steps  []string
+	LclUrl string
+}
+
+func (m *ConfigNextSteps) Init() tea.Cmd { return nil }
+
+func (m *ConfigNextSteps) Update(msg tea.Msg) (tea.Model, tea.Cmd) { return m, nil }
+
+func (m *ConfigNextSteps) View() string {
+	var b strings.Builder
+
+	fmt.Fprintln(&b, ui.Header("Next Steps"))
+	for _, step := range m.Steps {
+		fmt.Fprintln(&b, ui.StepNext(step))
+	}
+	if m.LclUrl != "" {
+		fmt.Fprintln(&b, ui.StepNext(fmt.Sprintf("Then check out your encrypted site at: %s", ui.URL(m.LclUrl))))
+	}
+
+	return b.String()
+}
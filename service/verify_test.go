@@ -64,7 +64,7 @@ func TestVerify(t *testing.T) {
 
 	acmeURL := cfg.AcmeURL("ankydotdev", "localhost", attachments[0].Relationships.Chain.Apid)
 
-	tlsCert, err := api.ProvisionCert(eab, []string{"ankydotdev.lcl.host"}, acmeURL)
+	tlsCert, err := api.ProvisionCert(ctx, eab, []string{"ankydotdev.lcl.host"}, acmeURL)
 	if err != nil {
 		t.Fatal(err)
 	}
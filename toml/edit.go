@@ -0,0 +1,489 @@
+package toml
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Edit updates doc in place from old to v, both encoded with the readonly
+// option applied. Only keys that differ between them are rewritten, added or
+// removed; comments, layout and any other keys or tables are preserved. If the
+// document can not be edited in place, an error is returned and doc should be
+// left as is.
+func Edit[T any](doc []byte, old, v T) ([]byte, error) {
+	var oldBuf, newBuf bytes.Buffer
+	if err := NewEncoder[T](&oldBuf).Encode(old); err != nil {
+		return nil, err
+	}
+	if err := NewEncoder[T](&newBuf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	out, err := edit(doc, oldBuf.Bytes(), newBuf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("can not edit document in place: %w", err)
+	}
+
+	var check map[string]any
+	if err := toml.Unmarshal(out, &check); err != nil {
+		return nil, fmt.Errorf("can not edit document in place: %w", err)
+	}
+	return out, nil
+}
+
+// document is a minimal line oriented view of a TOML document, enough to
+// locate the key/values and table headers in it.
+type document struct {
+	data []byte
+
+	entries []entry
+	headers []header
+}
+
+type entry struct {
+	table, key string // key is the full dotted path, including table
+
+	lineStart, valueStart, valueEnd, lineEnd int
+}
+
+func (e entry) value(data []byte) string { return string(data[e.valueStart:e.valueEnd]) }
+
+type header struct {
+	table string
+
+	lineStart, lineEnd int
+
+	comments bool // comment lines in the table body
+}
+
+type edition struct {
+	start, end int
+	text       string
+}
+
+func edit(doc, oldData, newData []byte) ([]byte, error) {
+	cur, err := parseDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+	oldDoc, err := parseDocument(oldData)
+	if err != nil {
+		return nil, err
+	}
+	newDoc, err := parseDocument(newData)
+	if err != nil {
+		return nil, err
+	}
+
+	oldValues := oldDoc.values()
+	newValues := newDoc.values()
+
+	var (
+		editions []edition
+		removed  = make(map[string]int)
+		kept     = make(map[string]int)
+		done     = make(map[string]bool)
+	)
+
+	for _, e := range cur.entries {
+		newValue, inNew := newValues[e.key]
+		oldValue, inOld := oldValues[e.key]
+
+		switch {
+		case inNew:
+			done[e.key] = true
+			kept[e.table]++
+			if inOld && oldValue == newValue {
+				continue
+			}
+			editions = append(editions, edition{start: e.valueStart, end: e.valueEnd, text: newValue})
+		case inOld:
+			removed[e.table]++
+			editions = append(editions, edition{start: e.lineStart, end: e.lineEnd})
+		default:
+			kept[e.table]++
+		}
+	}
+
+	// group additions by table, in the order they were encoded
+
+	var tables []string
+	additions := make(map[string][]entry)
+	for _, e := range newDoc.entries {
+		if done[e.key] {
+			continue
+		}
+		if oldValue, ok := oldValues[e.key]; ok && oldValue == newValues[e.key] {
+			continue // unchanged, but not in the document in a form we understand
+		}
+		if _, ok := additions[e.table]; !ok {
+			tables = append(tables, e.table)
+		}
+		additions[e.table] = append(additions[e.table], e)
+	}
+
+	var appended strings.Builder
+	for _, table := range tables {
+		var lines strings.Builder
+		for _, e := range additions[table] {
+			lines.Write(newData[e.lineStart:e.lineEnd])
+		}
+
+		if offset, ok := cur.insertionPoint(table); ok {
+			text := lines.String()
+			if table == "" && len(cur.headers) > 0 && offset == cur.headers[0].lineStart && cur.rootEntries() == 0 {
+				text += "\n"
+			}
+			editions = append(editions, edition{start: offset, end: offset, text: text})
+			kept[table] += len(additions[table])
+			continue
+		}
+
+		if idx := slices.IndexFunc(newDoc.headers, func(h header) bool { return h.table == table }); idx >= 0 {
+			h := newDoc.headers[idx]
+			appended.WriteString("\n")
+			appended.Write(newData[h.lineStart:h.lineEnd])
+		} else if table != "" {
+			return nil, fmt.Errorf("missing header for table %q", table)
+		}
+		appended.WriteString(lines.String())
+	}
+
+	// drop headers of tables emptied by this edit
+	for i, h := range cur.headers {
+		if removed[h.table] == 0 || kept[h.table] > 0 || h.comments {
+			continue
+		}
+
+		end := len(doc)
+		if i+1 < len(cur.headers) {
+			end = cur.headers[i+1].lineStart
+		}
+		editions = append(editions, edition{start: h.lineStart, end: end})
+	}
+
+	out := apply(doc, editions)
+
+	if appended.Len() > 0 {
+		if len(out) > 0 && !bytes.HasSuffix(out, []byte("\n")) {
+			out = append(out, '\n')
+		}
+		text := appended.String()
+		if len(bytes.TrimSpace(out)) == 0 {
+			text = strings.TrimPrefix(text, "\n")
+		}
+		out = append(out, text...)
+	}
+
+	if len(bytes.TrimSpace(out)) == 0 {
+		return []byte{}, nil
+	}
+	for bytes.HasSuffix(out, []byte("\n\n")) {
+		out = out[:len(out)-1]
+	}
+	return out, nil
+}
+
+func apply(doc []byte, editions []edition) []byte {
+	sort.SliceStable(editions, func(i, j int) bool { return editions[i].start < editions[j].start })
+
+	var (
+		out []byte
+		pos int
+	)
+	for _, ed := range editions {
+		if ed.start < pos {
+			if ed.end <= pos {
+				out = append(out, ed.text...)
+				continue
+			}
+			ed.start = pos // overlapping removals
+		}
+		out = append(out, doc[pos:ed.start]...)
+		out = append(out, ed.text...)
+		pos = ed.end
+	}
+	return append(out, doc[pos:]...)
+}
+
+func (d *document) values() map[string]string {
+	values := make(map[string]string, len(d.entries))
+	for _, e := range d.entries {
+		values[e.key] = e.value(d.data)
+	}
+	return values
+}
+
+func (d *document) rootEntries() int {
+	var n int
+	for _, e := range d.entries {
+		if e.table == "" {
+			n++
+		}
+	}
+	return n
+}
+
+// insertionPoint returns the offset just after the last key/value of table, or
+// after its header when it has none.
+func (d *document) insertionPoint(table string) (int, bool) {
+	offset, ok := -1, false
+	for _, e := range d.entries {
+		if e.table == table {
+			offset, ok = e.lineEnd, true
+		}
+	}
+	if ok {
+		return offset, true
+	}
+
+	if table == "" {
+		if len(d.headers) > 0 {
+			return d.headers[0].lineStart, true
+		}
+		return len(d.data), true
+	}
+
+	for _, h := range d.headers {
+		if h.table == table {
+			return h.lineEnd, true
+		}
+	}
+	return 0, false
+}
+
+func parseDocument(data []byte) (*document, error) {
+	d := &document{data: data}
+
+	var (
+		table  string
+		counts = make(map[string]int)
+	)
+
+	for pos := 0; pos < len(data); {
+		lineStart := pos
+		pos = skipSpace(data, pos)
+
+		switch {
+		case pos >= len(data):
+			continue
+		case data[pos] == '\n' || data[pos] == '\r':
+			pos = lineEnd(data, pos)
+		case data[pos] == '#':
+			if len(d.headers) > 0 {
+				d.headers[len(d.headers)-1].comments = true
+			}
+			pos = lineEnd(data, pos)
+		case data[pos] == '[':
+			array := pos+1 < len(data) && data[pos+1] == '['
+			start := pos + 1
+			if array {
+				start++
+			}
+
+			keys, next, err := parseKey(data, start)
+			if err != nil {
+				return nil, err
+			}
+			next = skipSpace(data, next)
+
+			closing := "]"
+			if array {
+				closing = "]]"
+			}
+			if !bytes.HasPrefix(data[next:], []byte(closing)) {
+				return nil, fmt.Errorf("invalid table header at offset %d", lineStart)
+			}
+
+			table = strings.Join(keys, ".")
+			if array {
+				name := table
+				table = fmt.Sprintf("%s[%d]", name, counts[name])
+				counts[name]++
+			}
+
+			pos = lineEnd(data, next+len(closing))
+			d.headers = append(d.headers, header{
+				table:     table,
+				lineStart: lineStart,
+				lineEnd:   pos,
+			})
+		default:
+			keys, next, err := parseKey(data, pos)
+			if err != nil {
+				return nil, err
+			}
+			next = skipSpace(data, next)
+			if next >= len(data) || data[next] != '=' {
+				return nil, fmt.Errorf("expected = at offset %d", next)
+			}
+
+			valueStart := skipSpace(data, next+1)
+			valueEnd, err := scanValue(data, valueStart)
+			if err != nil {
+				return nil, err
+			}
+
+			key := strings.Join(keys, ".")
+			if table != "" {
+				key = table + "." + key
+			}
+
+			pos = lineEnd(data, valueEnd)
+			d.entries = append(d.entries, entry{
+				table:      table,
+				key:        key,
+				lineStart:  lineStart,
+				valueStart: valueStart,
+				valueEnd:   valueEnd,
+				lineEnd:    pos,
+			})
+		}
+	}
+	return d, nil
+}
+
+func skipSpace(data []byte, pos int) int {
+	for pos < len(data) && (data[pos] == ' ' || data[pos] == '\t') {
+		pos++
+	}
+	return pos
+}
+
+// lineEnd returns the offset after the end of the line containing pos.
+func lineEnd(data []byte, pos int) int {
+	if i := bytes.IndexByte(data[pos:], '\n'); i >= 0 {
+		return pos + i + 1
+	}
+	return len(data)
+}
+
+func isBareKeyChar(c byte) bool {
+	return c == '-' || c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func parseKey(data []byte, pos int) ([]string, int, error) {
+	var keys []string
+	for {
+		pos = skipSpace(data, pos)
+		if pos >= len(data) {
+			return nil, pos, fmt.Errorf("unexpected end of key")
+		}
+
+		switch data[pos] {
+		case '"':
+			end, err := scanString(data, pos)
+			if err != nil {
+				return nil, pos, err
+			}
+			key, err := strconv.Unquote(string(data[pos:end]))
+			if err != nil {
+				return nil, pos, err
+			}
+			keys = append(keys, key)
+			pos = end
+		case '\'':
+			end, err := scanString(data, pos)
+			if err != nil {
+				return nil, pos, err
+			}
+			keys = append(keys, string(data[pos+1:end-1]))
+			pos = end
+		default:
+			start := pos
+			for pos < len(data) && isBareKeyChar(data[pos]) {
+				pos++
+			}
+			if pos == start {
+				return nil, pos, fmt.Errorf("invalid key at offset %d", pos)
+			}
+			keys = append(keys, string(data[start:pos]))
+		}
+
+		pos = skipSpace(data, pos)
+		if pos >= len(data) || data[pos] != '.' {
+			return keys, pos, nil
+		}
+		pos++
+	}
+}
+
+// scanString returns the offset after the string starting at pos, which may be
+// a basic, literal or multi-line string.
+func scanString(data []byte, pos int) (int, error) {
+	quote := data[pos]
+	delim := []byte{quote}
+	if bytes.HasPrefix(data[pos:], []byte{quote, quote, quote}) {
+		delim = []byte{quote, quote, quote}
+	}
+
+	for i := pos + len(delim); i < len(data); i++ {
+		switch {
+		case quote == '"' && data[i] == '\\':
+			i++
+		case len(delim) == 1 && data[i] == '\n':
+			return 0, fmt.Errorf("unterminated string at offset %d", pos)
+		case bytes.HasPrefix(data[i:], delim):
+			end := i + len(delim)
+			// multi-line strings may end with up to two extra quotes
+			for n := 0; len(delim) == 3 && n < 2 && end < len(data) && data[end] == quote; n++ {
+				end++
+			}
+			return end, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated string at offset %d", pos)
+}
+
+// scanValue returns the offset after the value starting at pos, excluding any
+// trailing whitespace or comment.
+func scanValue(data []byte, pos int) (int, error) {
+	var depth int
+	end := pos
+	for i := pos; i < len(data); {
+		switch c := data[i]; {
+		case c == '"' || c == '\'':
+			next, err := scanString(data, i)
+			if err != nil {
+				return 0, err
+			}
+			i, end = next, next
+		case c == '[' || c == '{':
+			depth++
+			i++
+			end = i
+		case c == ']' || c == '}':
+			depth--
+			i++
+			end = i
+		case c == '#':
+			if depth == 0 {
+				return end, nil
+			}
+			i = lineEnd(data, i)
+		case c == '\n' || c == '\r':
+			if depth == 0 {
+				return end, nil
+			}
+			i++
+		case c == ' ' || c == '\t' || c == ',':
+			i++
+			if c == ',' {
+				end = i
+			}
+		default:
+			i++
+			end = i
+		}
+	}
+	if depth != 0 {
+		return 0, fmt.Errorf("unterminated value at offset %d", pos)
+	}
+	return end, nil
+}
@@ -0,0 +1,276 @@
+package toml
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/require"
+)
+
+type editConfig struct {
+	Name   string `toml:"name,omitempty"`
+	Secret string `toml:"secret,omitempty,readonly"`
+
+	Org struct {
+		APID string `toml:"apid,omitempty"`
+	} `toml:"org,omitempty"`
+
+	Service struct {
+		APID      string   `toml:"apid,omitempty"`
+		CertStyle string   `toml:"cert-style,omitempty"`
+		Domains   []string `toml:"domains,omitempty"`
+	} `toml:"service,omitempty"`
+
+	Services []editService `toml:"services,omitempty"`
+}
+
+type editService struct {
+	Name string `toml:"name"`
+	APID string `toml:"apid,omitempty"`
+}
+
+func TestEdit(t *testing.T) {
+	tests := []struct {
+		name string
+
+		doc string
+		fn  func(*editConfig)
+
+		want string
+	}{
+		{
+			name: "update-preserves-comments",
+
+			doc: heredoc.Doc(`
+				# workspace settings
+				[org]
+				apid = "old-org" # the team org
+
+				[custom]
+				key = "kept"
+			`),
+			fn: func(cfg *editConfig) { cfg.Org.APID = "new-org" },
+
+			want: heredoc.Doc(`
+				# workspace settings
+				[org]
+				apid = 'new-org' # the team org
+
+				[custom]
+				key = "kept"
+			`),
+		},
+		{
+			name: "add-to-existing-table",
+
+			doc: heredoc.Doc(`
+				[service]
+				apid = "web"
+
+				[org]
+				apid = "test-org"
+			`),
+			fn: func(cfg *editConfig) { cfg.Service.CertStyle = "acme" },
+
+			want: heredoc.Doc(`
+				[service]
+				apid = "web"
+				cert-style = 'acme'
+
+				[org]
+				apid = "test-org"
+			`),
+		},
+		{
+			name: "add-table",
+
+			doc: heredoc.Doc(`
+				# just an org
+				[org]
+				apid = "test-org"
+			`),
+			fn: func(cfg *editConfig) { cfg.Service.APID = "web" },
+
+			want: heredoc.Doc(`
+				# just an org
+				[org]
+				apid = "test-org"
+
+				[service]
+				apid = 'web'
+			`),
+		},
+		{
+			name: "add-root-key",
+
+			doc: heredoc.Doc(`
+				[org]
+				apid = "test-org"
+			`),
+			fn: func(cfg *editConfig) { cfg.Name = "workspace" },
+
+			want: heredoc.Doc(`
+				name = 'workspace'
+
+				[org]
+				apid = "test-org"
+			`),
+		},
+		{
+			name: "remove-key-drops-empty-table",
+
+			doc: heredoc.Doc(`
+				[org]
+				apid = "test-org"
+
+				[service]
+				apid = "web"
+			`),
+			fn: func(cfg *editConfig) { cfg.Service.APID = "" },
+
+			want: heredoc.Doc(`
+				[org]
+				apid = "test-org"
+			`),
+		},
+		{
+			name: "remove-key-keeps-commented-table",
+
+			doc: heredoc.Doc(`
+				[service]
+				# set by lcl setup
+				apid = "web"
+			`),
+			fn: func(cfg *editConfig) { cfg.Service.APID = "" },
+
+			want: heredoc.Doc(`
+				[service]
+				# set by lcl setup
+			`),
+		},
+		{
+			name: "readonly-not-written",
+
+			doc: heredoc.Doc(`
+				[org]
+				apid = "test-org"
+			`),
+			fn: func(cfg *editConfig) { cfg.Secret = "s3cr3t" },
+
+			want: heredoc.Doc(`
+				[org]
+				apid = "test-org"
+			`),
+		},
+		{
+			name: "multiline-array",
+
+			doc: heredoc.Doc(`
+				[service]
+				domains = [
+				  "web.lcl.host", # primary
+				  "web.localhost",
+				]
+				apid = "web"
+			`),
+			fn: func(cfg *editConfig) { cfg.Service.Domains = []string{"app.lcl.host"} },
+
+			want: heredoc.Doc(`
+				[service]
+				domains = ['app.lcl.host']
+				apid = "web"
+			`),
+		},
+		{
+			name: "array-tables",
+
+			doc: heredoc.Doc(`
+				[[services]]
+				name = "api" # backend
+
+				[[services]]
+				name = "web"
+			`),
+			fn: func(cfg *editConfig) {
+				cfg.Services[1].APID = "web-service"
+				cfg.Services = append(cfg.Services, editService{Name: "admin"})
+			},
+
+			want: heredoc.Doc(`
+				[[services]]
+				name = "api" # backend
+
+				[[services]]
+				name = "web"
+				apid = 'web-service'
+
+				[[services]]
+				name = 'admin'
+			`),
+		},
+		{
+			name: "remove-everything",
+
+			doc: heredoc.Doc(`
+				[org]
+				apid = "test-org"
+			`),
+			fn: func(cfg *editConfig) { cfg.Org.APID = "" },
+
+			want: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var old editConfig
+			require.NoError(t, Unmarshal([]byte(test.doc), &old))
+
+			var cfg editConfig
+			require.NoError(t, Unmarshal([]byte(test.doc), &cfg))
+			test.fn(&cfg)
+
+			got, err := Edit([]byte(test.doc), old, cfg)
+			require.NoError(t, err)
+			require.Equal(t, test.want, string(got))
+		})
+	}
+}
+
+func TestEditError(t *testing.T) {
+	t.Run("inline-table", func(t *testing.T) {
+		doc := heredoc.Doc(`
+			org = { apid = "test-org" }
+		`)
+
+		var old editConfig
+		require.NoError(t, Unmarshal([]byte(doc), &old))
+
+		cfg := old
+		cfg.Org.APID = "new-org"
+
+		// an inline table can not be edited in place
+		_, err := Edit([]byte(doc), old, cfg)
+		require.Error(t, err)
+	})
+
+	t.Run("unparseable", func(t *testing.T) {
+		doc := []byte(heredoc.Doc(`
+			# keep this comment
+			name = "test"
+
+			[service
+			apid = "test-service"
+		`))
+		orig := string(doc)
+
+		old := editConfig{Name: "test"}
+		cfg := old
+		cfg.Name = "new-name"
+
+		got, err := Edit(doc, old, cfg)
+		require.Error(t, err)
+		require.Nil(t, got)
+		require.Equal(t, orig, string(doc))
+	})
+}
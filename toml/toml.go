@@ -1,16 +1,43 @@
 package toml
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"reflect"
+	"regexp"
 	"slices"
+	"strings"
 
 	"github.com/fatih/structtag"
 	"github.com/mohae/deepcopy"
 	"github.com/pelletier/go-toml/v2"
 )
 
-type Decoder = toml.Decoder
+type (
+	Decoder            = toml.Decoder
+	DecodeError        = toml.DecodeError
+	StrictMissingError = toml.StrictMissingError
+)
+
+func Marshal(v any) ([]byte, error) { return toml.Marshal(v) }
+
+func Unmarshal(data []byte, v any) error { return toml.Unmarshal(data, v) }
+
+var structFieldRegex = regexp.MustCompile(`struct field .* of type (\S+)$`)
+
+// DescribeError returns a single line description of a decode error, with the
+// line number when available.
+func DescribeError(err error) string {
+	var derr *DecodeError
+	if !errors.As(err, &derr) {
+		return err.Error()
+	}
+
+	row, _ := derr.Position()
+	msg := structFieldRegex.ReplaceAllString(strings.TrimPrefix(derr.Error(), "toml: "), "field of type $1")
+	return fmt.Sprintf("line %d: %s", row, msg)
+}
 
 func NewDecoder(r io.Reader) *Decoder { return toml.NewDecoder(r) }
 
@@ -39,7 +66,7 @@ func (e *Encoder[T]) Encode(v T) error {
 }
 
 func applyReadonlyOption(val reflect.Value) error {
-	if val.Kind() != reflect.Pointer && val.Elem().Kind() != reflect.Struct {
+	if val.Kind() != reflect.Pointer || val.Elem().Kind() != reflect.Struct {
 		return nil
 	}
 
@@ -3,6 +3,7 @@ package trust
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -90,6 +91,8 @@ func (c *Audit) RunTUI(ctx context.Context, drv *ui.Driver) error {
 
 	drv.Send(truststoremodels.AuditInfoMsg(auditInfo))
 
+	cli.SetResult(ctx, NewAuditResult(auditInfo, stores))
+
 	drv.Activate(ctx, &models.TrustAuditInfo{
 		AuditInfo: auditInfo,
 		Stores:    stores,
@@ -98,6 +101,56 @@ func (c *Audit) RunTUI(ctx context.Context, drv *ui.Driver) error {
 	return nil
 }
 
+// AuditResult is the result of trust audit in json output mode.
+type AuditResult struct {
+	Valid    []AuditCA `json:"valid"`
+	Missing  []AuditCA `json:"missing"`
+	Rotate   []AuditCA `json:"rotate"`
+	Expired  []AuditCA `json:"expired"`
+	PreValid []AuditCA `json:"pre_valid"`
+	Extra    []AuditCA `json:"extra"`
+}
+
+type AuditCA struct {
+	Subject    string    `json:"subject"`
+	UniqueName string    `json:"unique_name"`
+	NotBefore  time.Time `json:"not_before"`
+	NotAfter   time.Time `json:"not_after"`
+
+	Stores []string `json:"stores"` // stores the CA is present in
+}
+
+func NewAuditResult(info *truststore.AuditInfo, stores []truststore.Store) AuditResult {
+	cas := func(cas []*truststore.CA) []AuditCA {
+		out := make([]AuditCA, 0, len(cas))
+		for _, ca := range cas {
+			aca := AuditCA{
+				Subject:    ca.Subject.CommonName,
+				UniqueName: ca.UniqueName,
+				NotBefore:  ca.NotBefore,
+				NotAfter:   ca.NotAfter,
+				Stores:     []string{},
+			}
+			for _, store := range stores {
+				if info.IsPresent(ca, store) {
+					aca.Stores = append(aca.Stores, store.Description())
+				}
+			}
+			out = append(out, aca)
+		}
+		return out
+	}
+
+	return AuditResult{
+		Valid:    cas(info.Valid),
+		Missing:  cas(info.Missing),
+		Rotate:   cas(info.Rotate),
+		Expired:  cas(info.Expired),
+		PreValid: cas(info.PreValid),
+		Extra:    cas(info.Extra),
+	}
+}
+
 func (c *Audit) orgAPID(ctx context.Context, cfg *cli.Config, drv *ui.Driver) (string, error) {
 	if c.OrgAPID != "" {
 		return c.OrgAPID, nil
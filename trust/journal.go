@@ -0,0 +1,188 @@
+package trust
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/truststore"
+)
+
+// ErrJournalExists is returned when trust stores are updated while an
+// interrupted update has not been resumed or rolled back.
+var ErrJournalExists = cli.UserError{
+	Err: errors.New("a previous trust store update was interrupted, run `anchor trust resume` to finish it or `anchor trust rollback` to undo it"),
+}
+
+// Journal step states. A started step may or may not have been applied.
+const (
+	StepPending = "pending"
+	StepStarted = "started"
+	StepDone    = "done"
+)
+
+// Journal records the steps of a trust store update as they are applied, so
+// that an interrupted update can be resumed or rolled back.
+type Journal struct {
+	path string
+
+	StartedAt time.Time `json:"started_at"`
+
+	// Stores are the names of the trust stores, as in trust.stores.
+	Stores []string `json:"stores"`
+
+	CAs   []JournalCA   `json:"cas"`
+	Steps []JournalStep `json:"steps"`
+}
+
+type JournalCA struct {
+	UniqueName string `json:"unique_name"`
+	NickName   string `json:"nick_name,omitempty"`
+	Raw        []byte `json:"raw"`
+}
+
+// JournalStep installs the CA named CA into the store with the description
+// Store.
+type JournalStep struct {
+	Store string `json:"store"`
+	CA    string `json:"ca"`
+	State string `json:"state"`
+}
+
+// JournalPath returns the path of the trust journal, kept next to the user
+// config file. There is none for mock stores, which do not outlive the process.
+func JournalPath(cfg *cli.Config) string {
+	if cfg.Trust.MockMode || slices.Contains(cfg.Trust.Stores, truststore.StoreMock) {
+		return ""
+	}
+
+	path := cfg.UserConfigPath()
+	if path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(path), "trust-journal.json")
+}
+
+// NewJournal starts a journal at path for installing cas into the stores named
+// stores.
+func NewJournal(path string, stores []string, cas []*truststore.CA) *Journal {
+	j := &Journal{
+		path: path,
+
+		StartedAt: time.Now().UTC(),
+		Stores:    stores,
+	}
+	for _, ca := range cas {
+		j.CAs = append(j.CAs, JournalCA{
+			UniqueName: ca.UniqueName,
+			NickName:   ca.NickName,
+			Raw:        ca.Raw,
+		})
+	}
+	return j
+}
+
+// LoadJournal reads the journal at path, returning nil when there is none.
+func LoadJournal(path string) (*Journal, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	j := &Journal{path: path}
+	if err := json.Unmarshal(data, j); err != nil {
+		return nil, fmt.Errorf("reading trust journal %s: %w", path, err)
+	}
+	return j, nil
+}
+
+// AddStep adds a pending step for installing ca into store.
+func (j *Journal) AddStep(store truststore.Store, ca *truststore.CA) {
+	j.Steps = append(j.Steps, JournalStep{
+		Store: store.Description(),
+		CA:    ca.UniqueName,
+		State: StepPending,
+	})
+}
+
+// SetState records the state of the step for installing ca into store.
+func (j *Journal) SetState(store truststore.Store, ca *truststore.CA, state string) error {
+	i := slices.IndexFunc(j.Steps, func(step JournalStep) bool {
+		return step.Store == store.Description() && step.CA == ca.UniqueName
+	})
+	if i < 0 {
+		return fmt.Errorf("trust journal has no step for %s in %s", ca.UniqueName, store.Description())
+	}
+
+	j.Steps[i].State = state
+	return j.Save()
+}
+
+// Save writes the journal, replacing the previous version atomically.
+func (j *Journal) Save() error {
+	if j.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(j.path), 0700); err != nil {
+		return err
+	}
+
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, j.path)
+}
+
+// Remove deletes the journal, once its update is complete or undone.
+func (j *Journal) Remove() error {
+	if j.path == "" {
+		return nil
+	}
+	if err := os.Remove(j.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// LoadCAs returns the CAs of the journal, written as PEM files to dir for the
+// stores that install from files.
+func (j *Journal) LoadCAs(dir string) (map[string]*truststore.CA, error) {
+	cas := make(map[string]*truststore.CA, len(j.CAs))
+	for _, jca := range j.CAs {
+		cert, err := x509.ParseCertificate(jca.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("reading trust journal CA %s: %w", jca.UniqueName, err)
+		}
+
+		ca := &truststore.CA{
+			Certificate: cert,
+			NickName:    jca.NickName,
+			UniqueName:  jca.UniqueName,
+		}
+		if err := writeCAFile(ca, dir); err != nil {
+			return nil, err
+		}
+		cas[ca.UniqueName] = ca
+	}
+	return cas, nil
+}
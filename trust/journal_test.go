@@ -0,0 +1,222 @@
+package trust
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/truststore"
+	"github.com/anchordotdev/cli/ui/uitest"
+)
+
+func TestJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trust-journal.json")
+	store := new(truststore.Mock)
+	ca := testCA(t, "Journal Test CA")
+
+	journal := NewJournal(path, []string{"mock"}, []*truststore.CA{ca})
+	journal.AddStep(store, ca)
+	require.NoError(t, journal.Save())
+
+	require.NoError(t, journal.SetState(store, ca, StepStarted))
+
+	loaded, err := LoadJournal(path)
+	require.NoError(t, err)
+	require.Equal(t, []string{"mock"}, loaded.Stores)
+	require.Equal(t, []JournalStep{{Store: "Mock", CA: ca.UniqueName, State: StepStarted}}, loaded.Steps)
+
+	cas, err := loaded.LoadCAs(t.TempDir())
+	require.NoError(t, err)
+	require.True(t, ca.Equal(cas[ca.UniqueName]))
+	require.FileExists(t, cas[ca.UniqueName].FilePath)
+
+	require.Error(t, loaded.SetState(store, testCA(t, "Other CA"), StepDone))
+
+	require.NoError(t, loaded.Remove())
+	_, err = os.Stat(path)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	loaded, err = LoadJournal(path)
+	require.NoError(t, err)
+	require.Nil(t, loaded)
+}
+
+func TestRollbackJournal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	truststore.ResetMockCAs()
+	t.Cleanup(truststore.ResetMockCAs)
+
+	store := new(truststore.Mock)
+	installed, started, pending := testCA(t, "Installed CA"), testCA(t, "Started CA"), testCA(t, "Pending CA")
+
+	journal := NewJournal("", []string{"mock"}, []*truststore.CA{installed, started, pending})
+	for _, ca := range []*truststore.CA{installed, started, pending} {
+		journal.AddStep(store, ca)
+	}
+	require.NoError(t, journal.SetState(store, installed, StepDone))
+	require.NoError(t, journal.SetState(store, started, StepStarted))
+
+	_, err := store.InstallCA(installed)
+	require.NoError(t, err)
+
+	cas := map[string]*truststore.CA{
+		installed.UniqueName: installed,
+		started.UniqueName:   started,
+		pending.UniqueName:   pending,
+	}
+
+	drv, _ := uitest.TestTUI(ctx, t)
+	require.NoError(t, rollbackJournal(ctx, drv, journal, []truststore.Store{store}, cas))
+
+	require.Empty(t, truststore.MockCAs)
+	for _, step := range journal.Steps {
+		require.Equal(t, StepPending, step.State)
+	}
+}
+
+func TestUpdateRollback(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	truststore.ResetMockCAs()
+	t.Cleanup(truststore.ResetMockCAs)
+
+	cfg := new(cli.Config)
+	ctx = cli.ContextWithConfig(ctx, cfg)
+
+	mock := new(truststore.Mock)
+	failing := &failingStore{failAfter: 1}
+	stores := []truststore.Store{mock, failing}
+
+	first, second := testCA(t, "First CA"), testCA(t, "Second CA")
+	auditInfo := &truststore.AuditInfo{Missing: []*truststore.CA{first, second}}
+
+	path := filepath.Join(t.TempDir(), "trust-journal.json")
+	journal := NewJournal(path, []string{"mock", "failing"}, auditInfo.Missing)
+
+	drv, _ := uitest.TestTUI(ctx, t)
+	err := new(Command).update(ctx, cfg, drv, journal, stores, auditInfo)
+	require.ErrorIs(t, err, errInstallFailed)
+
+	require.Empty(t, truststore.MockCAs)
+	require.Empty(t, failing.cas)
+	require.NoFileExists(t, path)
+}
+
+var errInstallFailed = errors.New("install failed")
+
+// failingStore fails to install CAs once failAfter CAs are installed.
+type failingStore struct {
+	failAfter int
+
+	cas []*truststore.CA
+}
+
+func (s *failingStore) Check() (bool, error) { return true, nil }
+
+func (s *failingStore) Description() string { return "Failing" }
+
+func (s *failingStore) CheckCA(ca *truststore.CA) (bool, error) {
+	return slices.ContainsFunc(s.cas, ca.Equal), nil
+}
+
+func (s *failingStore) InstallCA(ca *truststore.CA) (bool, error) {
+	if len(s.cas) >= s.failAfter {
+		return false, errInstallFailed
+	}
+	s.cas = append(s.cas, ca)
+	return true, nil
+}
+
+func (s *failingStore) ListCAs() ([]*truststore.CA, error) { return s.cas, nil }
+
+func (s *failingStore) UninstallCA(ca *truststore.CA) (bool, error) {
+	s.cas = slices.DeleteFunc(s.cas, ca.Equal)
+	return true, nil
+}
+
+func TestResumeJournal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	truststore.ResetMockCAs()
+	t.Cleanup(truststore.ResetMockCAs)
+
+	store := new(truststore.Mock)
+	done, started, pending := testCA(t, "Done CA"), testCA(t, "Started CA"), testCA(t, "Pending CA")
+
+	journal := NewJournal("", []string{"mock"}, []*truststore.CA{done, started, pending})
+	for _, ca := range []*truststore.CA{done, started, pending} {
+		journal.AddStep(store, ca)
+	}
+	require.NoError(t, journal.SetState(store, done, StepDone))
+	require.NoError(t, journal.SetState(store, started, StepStarted))
+
+	for _, ca := range []*truststore.CA{done, started} {
+		_, err := store.InstallCA(ca)
+		require.NoError(t, err)
+	}
+
+	cas := map[string]*truststore.CA{
+		done.UniqueName:    done,
+		started.UniqueName: started,
+		pending.UniqueName: pending,
+	}
+
+	drv, _ := uitest.TestTUI(ctx, t)
+	require.NoError(t, resumeJournal(ctx, new(cli.Config), drv, journal, []truststore.Store{store}, cas))
+
+	require.Len(t, truststore.MockCAs, 3)
+	for _, step := range journal.Steps {
+		require.Equal(t, StepDone, step.State)
+	}
+
+	t.Run("missing store", func(t *testing.T) {
+		journal.Steps[0].State = StepPending
+
+		err := resumeJournal(ctx, new(cli.Config), drv, journal, nil, cas)
+		require.ErrorContains(t, err, "trust store Mock of the interrupted update is not available")
+	})
+}
+
+func testCA(t *testing.T, commonName string) *truststore.CA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &truststore.CA{
+		Certificate: cert,
+		UniqueName:  commonName,
+	}
+}
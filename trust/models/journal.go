@@ -0,0 +1,122 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/anchordotdev/cli/truststore"
+	"github.com/anchordotdev/cli/ui"
+)
+
+var (
+	TrustResumeHeader = ui.Section{
+		Name: "TrustResumeHeader",
+		Model: ui.MessageLines{
+			ui.Header(fmt.Sprintf("Resume an Interrupted Trust Store Update %s", ui.Whisper("`anchor trust resume`"))),
+		},
+	}
+
+	TrustRollbackHeader = ui.Section{
+		Name: "TrustRollbackHeader",
+		Model: ui.MessageLines{
+			ui.Header(fmt.Sprintf("Roll Back an Interrupted Trust Store Update %s", ui.Whisper("`anchor trust rollback`"))),
+		},
+	}
+
+	TrustJournalNone = ui.Section{
+		Name: "TrustJournalNone",
+		Model: ui.MessageLines{
+			ui.StepDone("No interrupted trust store update found, nothing to do."),
+		},
+	}
+)
+
+type TrustJournalFound struct {
+	StartedAt   time.Time
+	Done, Total int
+}
+
+func (m *TrustJournalFound) Init() tea.Cmd { return nil }
+
+func (m *TrustJournalFound) Update(msg tea.Msg) (tea.Model, tea.Cmd) { return m, nil }
+
+func (m *TrustJournalFound) View() string {
+	var b strings.Builder
+	fmt.Fprintln(&b, ui.StepDone(fmt.Sprintf("Found a trust store update interrupted after %d of %d steps, started %s.",
+		m.Done,
+		m.Total,
+		ui.Whisper(m.StartedAt.Local().Format(time.DateTime)),
+	)))
+	return b.String()
+}
+
+type TrustRollbackStore struct {
+	Store truststore.Store
+
+	removing    *truststore.CA
+	removed     []string
+	commonNames []string
+
+	spinner spinner.Model
+}
+
+func (m *TrustRollbackStore) Init() tea.Cmd {
+	m.spinner = ui.WaitingSpinner()
+
+	return m.spinner.Tick
+}
+
+type (
+	TrustStoreRemovingCAMsg struct {
+		truststore.CA
+	}
+
+	TrustStoreRemovedCAMsg struct {
+		truststore.CA
+	}
+)
+
+func (m *TrustRollbackStore) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case TrustStoreRemovingCAMsg:
+		m.removing = &msg.CA
+		return m, nil
+	case TrustStoreRemovedCAMsg:
+		m.removing = nil
+		m.removed = append(m.removed, fmt.Sprintf("%s [%s]",
+			ui.Underline(msg.CA.Subject.CommonName),
+			ui.Whisper(msg.CA.PublicKeyAlgorithm.String()),
+		))
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(msg)
+	return m, cmd
+}
+
+func (m *TrustRollbackStore) View() string {
+	var b strings.Builder
+
+	if len(m.removed) > 0 {
+		fmt.Fprintln(&b, ui.StepDone(fmt.Sprintf("Rolled back %s: removed %s",
+			ui.Emphasize(m.Store.Description()),
+			strings.Join(m.removed, ", "),
+		)))
+	}
+
+	if m.removing != nil {
+		fmt.Fprintln(&b, ui.StepInProgress(fmt.Sprintf("Rolling back %s: removing %s %s… %s",
+			ui.Emphasize(m.Store.Description()),
+			ui.Underline(m.removing.Subject.CommonName),
+			ui.Whisper(m.removing.PublicKeyAlgorithm.String()),
+			m.spinner.View(),
+		)))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,123 @@
+package trust
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/trust/models"
+	"github.com/anchordotdev/cli/truststore"
+	"github.com/anchordotdev/cli/ui"
+)
+
+var CmdTrustResume = cli.NewCmd[Resume](CmdTrust, "resume", func(cmd *cobra.Command) {
+	cfg := cli.ConfigFromCmd(cmd)
+
+	cmd.Flags().BoolVar(&cfg.Trust.NoSudo, "no-sudo", cli.Defaults.Trust.NoSudo, "Disable sudo prompts.")
+})
+
+type Resume struct{}
+
+func (c Resume) UI() cli.UI {
+	return cli.UI{
+		RunTUI: c.runTUI,
+	}
+}
+
+func (c *Resume) runTUI(ctx context.Context, drv *ui.Driver) error {
+	cfg := cli.ConfigFromContext(ctx)
+
+	drv.Activate(ctx, models.TrustResumeHeader)
+
+	journal, err := LoadJournal(JournalPath(cfg))
+	if err != nil {
+		return err
+	}
+	if journal == nil {
+		drv.Activate(ctx, models.TrustJournalNone)
+		return nil
+	}
+	drv.Activate(ctx, journalFound(journal))
+
+	stores, cas, cleanup, err := loadJournalStores(ctx, cfg, drv, journal)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	return resumeJournal(ctx, cfg, drv, journal, stores, cas)
+}
+
+// resumeJournal installs the CAs of the steps of journal that are not done,
+// then removes the journal. Started steps are only redone when the CA turns
+// out to be missing. The journal is kept on error, to resume or roll back
+// again.
+func resumeJournal(ctx context.Context, cfg *cli.Config, drv *ui.Driver, journal *Journal, stores []truststore.Store, cas map[string]*truststore.CA) error {
+	byDescription := make(map[string]truststore.Store, len(stores))
+	for _, store := range stores {
+		byDescription[store.Description()] = store
+	}
+
+	var pending []JournalStep
+	for _, step := range journal.Steps {
+		if step.State == StepDone {
+			continue
+		}
+		if _, _, err := journalStep(step, byDescription, cas); err != nil {
+			return err
+		}
+		pending = append(pending, step)
+	}
+
+	for _, store := range stores {
+		var steps []JournalStep
+		for _, step := range pending {
+			if step.Store == store.Description() {
+				steps = append(steps, step)
+			}
+		}
+		if len(steps) == 0 {
+			continue
+		}
+
+		drv.Activate(ctx, &models.TrustUpdateStore{
+			Config:       cfg,
+			MissingCount: len(steps),
+			Store:        store,
+		})
+
+		for _, step := range steps {
+			ca := cas[step.CA]
+
+			if step.State == StepStarted {
+				if ok, err := store.CheckCA(ca); err != nil {
+					return classifyError(err)
+				} else if ok {
+					drv.Send(models.TrustStoreExistingCAMsg{CA: *ca})
+					if err := journal.SetState(store, ca, StepDone); err != nil {
+						return err
+					}
+					continue
+				}
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			if err := journal.SetState(store, ca, StepStarted); err != nil {
+				return err
+			}
+			drv.Send(models.TrustStoreInstallingCAMsg{CA: *ca})
+			if _, err := store.InstallCA(ca); err != nil {
+				return classifyError(err)
+			}
+			drv.Send(models.TrustStoreInstalledCAMsg{CA: *ca})
+			if err := journal.SetState(store, ca, StepDone); err != nil {
+				return err
+			}
+		}
+	}
+
+	return journal.Remove()
+}
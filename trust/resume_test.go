@@ -0,0 +1,20 @@
+package trust
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchordotdev/cli/cmdtest"
+)
+
+func TestCmdTrustResume(t *testing.T) {
+	t.Run("--help", func(t *testing.T) {
+		cmdtest.TestHelp(t, CmdTrustResume, "trust", "resume", "--help")
+	})
+
+	t.Run("--no-sudo", func(t *testing.T) {
+		cfg := cmdtest.TestCfg(t, CmdTrustResume, "--no-sudo")
+		require.Equal(t, true, cfg.Trust.NoSudo)
+	})
+}
@@ -0,0 +1,177 @@
+package trust
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchordotdev/cli"
+	"github.com/anchordotdev/cli/trust/models"
+	"github.com/anchordotdev/cli/truststore"
+	"github.com/anchordotdev/cli/ui"
+)
+
+var CmdTrustRollback = cli.NewCmd[Rollback](CmdTrust, "rollback", func(cmd *cobra.Command) {
+	cfg := cli.ConfigFromCmd(cmd)
+
+	cmd.Flags().BoolVar(&cfg.Trust.NoSudo, "no-sudo", cli.Defaults.Trust.NoSudo, "Disable sudo prompts.")
+})
+
+type Rollback struct{}
+
+func (c Rollback) UI() cli.UI {
+	return cli.UI{
+		RunTUI: c.runTUI,
+	}
+}
+
+func (c *Rollback) runTUI(ctx context.Context, drv *ui.Driver) error {
+	cfg := cli.ConfigFromContext(ctx)
+
+	drv.Activate(ctx, models.TrustRollbackHeader)
+
+	journal, err := LoadJournal(JournalPath(cfg))
+	if err != nil {
+		return err
+	}
+	if journal == nil {
+		drv.Activate(ctx, models.TrustJournalNone)
+		return nil
+	}
+	drv.Activate(ctx, journalFound(journal))
+
+	stores, cas, cleanup, err := loadJournalStores(ctx, cfg, drv, journal)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	return rollbackJournal(ctx, drv, journal, stores, cas)
+}
+
+// rollbackAfter undoes the applied steps of journal after an update failed
+// with cause, and returns cause. A canceled update no longer has a running UI
+// to pause for sudo prompts, so the stores are reloaded without one.
+func rollbackAfter(ctx context.Context, cfg *cli.Config, drv *ui.Driver, journal *Journal, stores []truststore.Store, missing []*truststore.CA, cause error) error {
+	if ctx.Err() != nil {
+		ctx = context.WithoutCancel(ctx)
+
+		var err error
+		if stores, err = LoadStores(ctx, nil); err != nil {
+			return errors.Join(cause, err)
+		}
+	}
+
+	cas := make(map[string]*truststore.CA, len(missing))
+	for _, ca := range missing {
+		cas[ca.UniqueName] = ca
+	}
+
+	if err := rollbackJournal(ctx, drv, journal, stores, cas); err != nil {
+		return fmt.Errorf("%w\n\nRolling back the trust store update failed, run `anchor trust rollback` to retry: %w", cause, err)
+	}
+	return cause
+}
+
+// rollbackJournal uninstalls the CAs of the started and done steps of journal,
+// in reverse order, then removes the journal. Started steps are only undone
+// when the CA turns out to be installed.
+func rollbackJournal(ctx context.Context, drv *ui.Driver, journal *Journal, stores []truststore.Store, cas map[string]*truststore.CA) error {
+	byDescription := make(map[string]truststore.Store, len(stores))
+	for _, store := range stores {
+		byDescription[store.Description()] = store
+	}
+
+	var current truststore.Store
+	for i := len(journal.Steps) - 1; i >= 0; i-- {
+		step := journal.Steps[i]
+		if step.State == StepPending {
+			continue
+		}
+
+		store, ca, err := journalStep(step, byDescription, cas)
+		if err != nil {
+			return err
+		}
+
+		if step.State == StepStarted {
+			if ok, err := store.CheckCA(ca); err != nil {
+				return classifyError(err)
+			} else if !ok {
+				if err := journal.SetState(store, ca, StepPending); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if store != current {
+			current = store
+			drv.Activate(ctx, &models.TrustRollbackStore{Store: store})
+		}
+
+		drv.Send(models.TrustStoreRemovingCAMsg{CA: *ca})
+		if _, err := store.UninstallCA(ca); err != nil {
+			return classifyError(err)
+		}
+		drv.Send(models.TrustStoreRemovedCAMsg{CA: *ca})
+
+		if err := journal.SetState(store, ca, StepPending); err != nil {
+			return err
+		}
+	}
+
+	return journal.Remove()
+}
+
+// loadJournalStores loads the stores and CAs of journal. The returned cleanup
+// removes the CA files written for the stores.
+func loadJournalStores(ctx context.Context, cfg *cli.Config, drv *ui.Driver, journal *Journal) ([]truststore.Store, map[string]*truststore.CA, func(), error) {
+	cfg.Trust.Stores = journal.Stores
+
+	stores, err := LoadStores(ctx, drv)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "anchor-trust")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	cleanup := func() { os.RemoveAll(tmpDir) }
+
+	cas, err := journal.LoadCAs(tmpDir)
+	if err != nil {
+		cleanup()
+		return nil, nil, nil, err
+	}
+	return stores, cas, cleanup, nil
+}
+
+func journalStep(step JournalStep, stores map[string]truststore.Store, cas map[string]*truststore.CA) (truststore.Store, *truststore.CA, error) {
+	store, ok := stores[step.Store]
+	if !ok {
+		return nil, nil, cli.UserError{Err: fmt.Errorf("trust store %s of the interrupted update is not available", step.Store)}
+	}
+	ca, ok := cas[step.CA]
+	if !ok {
+		return nil, nil, fmt.Errorf("trust journal is missing CA %s", step.CA)
+	}
+	return store, ca, nil
+}
+
+func journalFound(journal *Journal) *models.TrustJournalFound {
+	mdl := &models.TrustJournalFound{
+		StartedAt: journal.StartedAt,
+		Total:     len(journal.Steps),
+	}
+	for _, step := range journal.Steps {
+		if step.State == StepDone {
+			mdl.Done++
+		}
+	}
+	return mdl
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/anchordotdev/cli/auth"
 	"github.com/anchordotdev/cli/component"
 	componentmodels "github.com/anchordotdev/cli/component/models"
+	"github.com/anchordotdev/cli/eventlog"
 	"github.com/anchordotdev/cli/ext509"
 	"github.com/anchordotdev/cli/ext509/oid"
 	"github.com/anchordotdev/cli/trust/models"
@@ -67,6 +68,12 @@ func (c *Command) run(ctx context.Context, drv *ui.Driver) error {
 func (c *Command) Perform(ctx context.Context, drv *ui.Driver) error {
 	cfg := cli.ConfigFromContext(ctx)
 
+	if journal, err := LoadJournal(JournalPath(cfg)); err != nil {
+		return err
+	} else if journal != nil {
+		return ErrJournalExists
+	}
+
 	if isVMOrContainer(cfg) {
 		drv.Activate(ctx, &models.VMHint{})
 	}
@@ -88,15 +95,17 @@ func (c *Command) Perform(ctx context.Context, drv *ui.Driver) error {
 		return nil
 	}
 
-	confirmCh := make(chan struct{})
-	drv.Activate(ctx, &models.TrustUpdateConfirm{
-		ConfirmCh: confirmCh,
-	})
+	if !cfg.NonInteractive {
+		confirmCh := make(chan struct{})
+		drv.Activate(ctx, &models.TrustUpdateConfirm{
+			ConfirmCh: confirmCh,
+		})
 
-	select {
-	case <-confirmCh:
-	case <-ctx.Done():
-		return ctx.Err()
+		select {
+		case <-confirmCh:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 
 	tmpDir, err := os.MkdirTemp("", "anchor-trust")
@@ -112,6 +121,31 @@ func (c *Command) Perform(ctx context.Context, drv *ui.Driver) error {
 		}
 	}
 
+	journal := NewJournal(JournalPath(cfg), cfg.Trust.Stores, auditInfo.Missing)
+	return c.update(ctx, cfg, drv, journal, stores, auditInfo)
+}
+
+// update installs the missing CAs into stores, recording each step in journal.
+// A failed update is rolled back before the error is returned.
+func (c *Command) update(ctx context.Context, cfg *cli.Config, drv *ui.Driver, journal *Journal, stores []truststore.Store, auditInfo *truststore.AuditInfo) error {
+	for _, store := range stores {
+		for _, ca := range auditInfo.Missing {
+			if !auditInfo.IsPresent(ca, store) {
+				journal.AddStep(store, ca)
+			}
+		}
+	}
+	if err := journal.Save(); err != nil {
+		return err
+	}
+
+	if err := c.install(ctx, cfg, drv, journal, stores, auditInfo); err != nil {
+		return rollbackAfter(ctx, cfg, drv, journal, stores, auditInfo.Missing, err)
+	}
+	return journal.Remove()
+}
+
+func (c *Command) install(ctx context.Context, cfg *cli.Config, drv *ui.Driver, journal *Journal, stores []truststore.Store, auditInfo *truststore.AuditInfo) error {
 	for _, store := range stores {
 		drv.Activate(ctx, &models.TrustUpdateStore{
 			Config:       cfg,
@@ -123,6 +157,13 @@ func (c *Command) Perform(ctx context.Context, drv *ui.Driver) error {
 				drv.Send(models.TrustStoreExistingCAMsg{CA: *ca})
 				continue
 			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			if err := journal.SetState(store, ca, StepStarted); err != nil {
+				return err
+			}
 			drv.Send(models.TrustStoreInstallingCAMsg{CA: *ca})
 			if ok, err := store.InstallCA(ca); err != nil {
 				return classifyError(err)
@@ -130,9 +171,11 @@ func (c *Command) Perform(ctx context.Context, drv *ui.Driver) error {
 				panic("impossible")
 			}
 			drv.Send(models.TrustStoreInstalledCAMsg{CA: *ca})
+			if err := journal.SetState(store, ca, StepDone); err != nil {
+				return err
+			}
 		}
 	}
-
 	return nil
 }
 
@@ -341,7 +384,7 @@ func LoadStores(ctx context.Context, drv *ui.Driver) ([]truststore.Store, error)
 	var stores []truststore.Store
 	for _, storeName := range trustStores {
 		switch storeName {
-		case "system":
+		case truststore.StoreSystem:
 			systemStore := &truststore.Platform{
 				HomeDir: homeDir,
 
@@ -350,7 +393,7 @@ func LoadStores(ctx context.Context, drv *ui.Driver) ([]truststore.Store, error)
 			}
 
 			stores = append(stores, systemStore)
-		case "nss":
+		case truststore.StoreNSS:
 			nssStore := &truststore.NSS{
 				HomeDir: homeDir,
 
@@ -361,7 +404,7 @@ func LoadStores(ctx context.Context, drv *ui.Driver) ([]truststore.Store, error)
 			if available, _ := nssStore.Check(); available {
 				stores = append(stores, nssStore)
 			}
-		case "homebrew":
+		case truststore.StoreHomebrew:
 			brewStore := &truststore.Brew{
 				RootDir: "/",
 
@@ -372,11 +415,17 @@ func LoadStores(ctx context.Context, drv *ui.Driver) ([]truststore.Store, error)
 			if available, _ := brewStore.Check(); available {
 				stores = append(stores, brewStore)
 			}
-		case "mock":
+		case truststore.StoreMock:
 			stores = append(stores, new(truststore.Mock))
 		}
 	}
 
+	if log := eventlog.FromContext(ctx); log != nil {
+		for i, store := range stores {
+			stores[i] = truststore.LogStore(store, log)
+		}
+	}
+
 	return stores, nil
 }
 
@@ -0,0 +1,61 @@
+package truststore
+
+import (
+	"github.com/anchordotdev/cli/eventlog"
+)
+
+// LogStore wraps store to record each of its operations in log.
+func LogStore(store Store, log *eventlog.Log) Store {
+	if log == nil {
+		return store
+	}
+	return &loggedStore{Store: store, log: log}
+}
+
+type loggedStore struct {
+	Store
+
+	log *eventlog.Log
+}
+
+func (s *loggedStore) Check() (bool, error) {
+	ok, err := s.Store.Check()
+	s.event(OpCheck, nil, eventlog.Fields{"ok": ok}, err)
+	return ok, err
+}
+
+func (s *loggedStore) CheckCA(ca *CA) (bool, error) {
+	ok, err := s.Store.CheckCA(ca)
+	s.event(OpCheck, ca, eventlog.Fields{"ok": ok}, err)
+	return ok, err
+}
+
+func (s *loggedStore) InstallCA(ca *CA) (bool, error) {
+	ok, err := s.Store.InstallCA(ca)
+	s.event(OpInstall, ca, eventlog.Fields{"ok": ok}, err)
+	return ok, err
+}
+
+func (s *loggedStore) ListCAs() ([]*CA, error) {
+	cas, err := s.Store.ListCAs()
+	s.event(OpList, nil, eventlog.Fields{"count": len(cas)}, err)
+	return cas, err
+}
+
+func (s *loggedStore) UninstallCA(ca *CA) (bool, error) {
+	ok, err := s.Store.UninstallCA(ca)
+	s.event(OpUninstall, ca, eventlog.Fields{"ok": ok}, err)
+	return ok, err
+}
+
+func (s *loggedStore) event(op Op, ca *CA, fields eventlog.Fields, err error) {
+	fields["op"] = op
+	fields["store"] = s.Store.Description()
+	if ca != nil {
+		fields["ca"] = ca.UniqueName
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	s.log.Event("truststore", fields)
+}
@@ -0,0 +1,45 @@
+package truststore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/anchordotdev/cli/eventlog"
+)
+
+func TestLogStore(t *testing.T) {
+	ResetMockCAs()
+
+	var buf bytes.Buffer
+	testStore(t, LogStore(new(Mock), eventlog.New(&buf)))
+
+	var ops []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var event struct {
+			Type  string `json:"type"`
+			Op    string `json:"op"`
+			Store string `json:"store"`
+			CA    string `json:"ca"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatal(err)
+		}
+		if event.Type != "truststore" || event.Store != "Mock" {
+			t.Fatalf("want truststore event for Mock store, got %s", scanner.Text())
+		}
+		if event.CA != "" && event.CA != ca.UniqueName {
+			t.Fatalf("want event for %q ca, got %s", ca.UniqueName, scanner.Text())
+		}
+		ops = append(ops, event.Op)
+	}
+
+	for _, op := range []Op{OpCheck, OpInstall, OpList, OpUninstall} {
+		if !slices.Contains(ops, string(op)) {
+			t.Errorf("want %q operation in log, got %v", op, ops)
+		}
+	}
+}
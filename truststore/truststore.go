@@ -12,6 +12,16 @@ import (
 	"strings"
 )
 
+const (
+	StoreHomebrew = "homebrew"
+	StoreMock     = "mock"
+	StoreNSS      = "nss"
+	StoreSystem   = "system"
+)
+
+// StoreNames are the trust stores that can be selected with trust.stores.
+var StoreNames = []string{StoreHomebrew, StoreMock, StoreNSS, StoreSystem}
+
 type Store interface {
 	Check() (bool, error)
 	Description() string
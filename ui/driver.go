@@ -2,6 +2,7 @@ package ui
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"reflect"
@@ -10,8 +11,12 @@ import (
 	"sync"
 	"unicode/utf8"
 
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/muesli/termenv"
+
+	"github.com/anchordotdev/cli/eventlog"
 )
 
 var (
@@ -44,10 +49,28 @@ type Driver struct {
 	golden       string
 	lastView     string
 	replacements []string
+
+	events      *json.Encoder // json output mode
+	eventsMutex sync.Mutex
+
+	recorder *Recorder
+
+	log *eventlog.Log
+}
+
+// Event is written for each activated model and sent message in json output
+// mode.
+type Event struct {
+	Type string `json:"type"` // activate or message
+
+	Model   string `json:"model,omitempty"`
+	Message string `json:"message,omitempty"`
+
+	Data json.RawMessage `json:"data,omitempty"`
 }
 
 func NewDriverTest(ctx context.Context) *Driver {
-	drv := new(Driver)
+	drv := &Driver{log: eventlog.FromContext(ctx)}
 
 	opts := []tea.ProgramOption{
 		tea.WithInputTTY(),
@@ -61,7 +84,7 @@ func NewDriverTest(ctx context.Context) *Driver {
 }
 
 func NewDriverTUI(ctx context.Context) (*Driver, Program) {
-	drv := new(Driver)
+	drv := &Driver{log: eventlog.FromContext(ctx)}
 
 	opts := []tea.ProgramOption{
 		tea.WithInputTTY(),
@@ -73,9 +96,31 @@ func NewDriverTUI(ctx context.Context) (*Driver, Program) {
 	return drv, drv.Program
 }
 
+// NewDriverOutput returns a driver that runs models without rendering them, for
+// the json and text output modes. In json mode, events are written to w as
+// newline delimited JSON.
+func NewDriverOutput(ctx context.Context, format string, w io.Writer) (*Driver, Program) {
+	drv := &Driver{log: eventlog.FromContext(ctx)}
+	if format == "json" {
+		drv.events = json.NewEncoder(w)
+	}
+
+	opts := []tea.ProgramOption{
+		tea.WithInput(nil),
+		tea.WithContext(ctx),
+		tea.WithoutRenderer(),
+		tea.WithoutSignalHandler(),
+	}
+
+	drv.Program = tea.NewProgram(drv, opts...)
+
+	return drv, drv.Program
+}
+
 func NewDriverTTY(ctx context.Context) *Driver {
 	return &Driver{
 		TTY: termenv.DefaultOutput().TTY(),
+		log: eventlog.FromContext(ctx),
 	}
 }
 
@@ -103,12 +148,82 @@ func (d *Driver) Activate(ctx context.Context, model tea.Model) {
 	}
 }
 
+func (d *Driver) Send(msg tea.Msg) {
+	d.emit(msg)
+	d.Program.Send(msg)
+}
+
+func (d *Driver) emit(msg tea.Msg) {
+	if d.events == nil {
+		return
+	}
+
+	var event Event
+	switch msg := msg.(type) {
+	case activateMsg:
+		event = Event{Type: "activate", Model: modelName(msg.Model)}
+	case stopMsg, pauseMsg:
+		return
+	default:
+		event = Event{Type: "message", Message: fmt.Sprintf("%T", msg), Data: eventData(msg)}
+	}
+
+	d.eventsMutex.Lock()
+	defer d.eventsMutex.Unlock()
+
+	_ = d.events.Encode(event)
+}
+
+// eventData is the JSON encoding of messages with a simple value, such as a
+// status, or that implement json.Marshaler.
+func eventData(msg tea.Msg) json.RawMessage {
+	if _, ok := msg.(json.Marshaler); !ok {
+		switch reflect.ValueOf(msg).Kind() {
+		case reflect.Bool, reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		default:
+			return nil
+		}
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// logMsg records msg in the event log, skipping animation ticks.
+func (d *Driver) logMsg(msg tea.Msg) {
+	if d.log == nil {
+		return
+	}
+
+	switch msg := msg.(type) {
+	case activateMsg:
+		d.log.Event("activate", eventlog.Fields{"model": modelName(msg.Model)})
+	case spinner.TickMsg, cursor.BlinkMsg, stopMsg, pauseMsg:
+	default:
+		fields := eventlog.Fields{"message": fmt.Sprintf("%T", msg)}
+		if data := eventData(msg); data != nil {
+			fields["data"] = data
+		}
+		d.log.Event("message", fields)
+	}
+}
+
 func (d *Driver) Golden() string {
 	d.goldenMutex.Lock()
 	defer d.goldenMutex.Unlock()
 	return d.golden
 }
 
+// Record records the rendered frames of the TUI with rec, with the
+// replacements of Replace applied.
+func (d *Driver) Record(rec *Recorder) {
+	d.recorder = rec
+}
+
 func (d *Driver) Replace(unsafe, safe string) {
 	d.goldenMutex.Lock()
 	defer d.goldenMutex.Unlock()
@@ -138,6 +253,8 @@ func (d *Driver) Init() tea.Cmd {
 }
 
 func (d *Driver) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	d.logMsg(msg)
+
 	switch msg := msg.(type) {
 	case activateMsg:
 		d.models = append(d.models, msg.Model)
@@ -154,6 +271,10 @@ func (d *Driver) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case tea.KeyCtrlC:
 			return d, tea.Quit
 		}
+	case tea.WindowSizeMsg:
+		if d.recorder != nil {
+			d.recorder.Resize(msg.Width, msg.Height)
+		}
 	case HideModelsMsg:
 		for _, mdl := range d.models {
 			mdl.Update(msg)
@@ -189,6 +310,15 @@ func (d *Driver) ErrorView() string {
 	return normalizedOut
 }
 
+// PlainView is the output of every activated model, without styling.
+func (d *Driver) PlainView() string {
+	lines := strings.Split(d.ErrorView(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.TrimLeft(strings.Join(lines, "\n"), "\n")
+}
+
 func (d *Driver) View() string {
 	var out string
 	for _, mdl := range d.models {
@@ -198,10 +328,21 @@ func (d *Driver) View() string {
 	if out != "" {
 		d.recordGolden(d.replaced(out))
 	}
+	if d.recorder != nil {
+		d.recorder.Frame(d.redacted(out))
+	}
 
 	return out
 }
 
+// redacted applies the replacements to out, keeping the spinner frames.
+func (d *Driver) redacted(out string) string {
+	d.goldenMutex.Lock()
+	defer d.goldenMutex.Unlock()
+
+	return strings.NewReplacer(d.replacements...).Replace(out)
+}
+
 func (d *Driver) replaced(out string) string {
 	replaced := spinnerReplacer.Replace(out)
 	replaced = strings.NewReplacer(d.replacements...).Replace(replaced)
@@ -216,14 +357,7 @@ func (d *Driver) recordGolden(out string) {
 		return
 	}
 
-	var section string
-	if mdl, ok := d.active.(interface{ Section() string }); ok {
-		section = mdl.Section()
-	} else if kind := reflect.TypeOf(d.active).Kind(); kind == reflect.Interface || kind == reflect.Pointer {
-		section = reflect.TypeOf(d.active).Elem().Name()
-	} else {
-		section = reflect.TypeOf(d.active).Name()
-	}
+	section := modelName(d.active)
 
 	separator := fmt.Sprintf("─── %s ", section)
 	if separatorRuneCount := utf8.RuneCountInString(separator); separatorRuneCount < 80 {
@@ -237,6 +371,16 @@ func (d *Driver) recordGolden(out string) {
 	d.golden += strings.Join([]string{separator, out}, "\n")
 }
 
+func modelName(mdl tea.Model) string {
+	if mdl, ok := mdl.(interface{ Section() string }); ok {
+		return mdl.Section()
+	}
+	if kind := reflect.TypeOf(mdl).Kind(); kind == reflect.Interface || kind == reflect.Pointer {
+		return reflect.TypeOf(mdl).Elem().Name()
+	}
+	return reflect.TypeOf(mdl).Name()
+}
+
 var (
 	quitPtr = reflect.ValueOf(tea.Quit).Pointer()
 	exitPtr = reflect.ValueOf(Exit).Pointer()
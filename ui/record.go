@@ -0,0 +1,212 @@
+package ui
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CastHeader is the first line of an asciicast v2 recording.
+type CastHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// CastEvent is an event of an asciicast v2 recording: output ("o") or a
+// terminal resize ("r") at Time seconds into the recording.
+type CastEvent struct {
+	Time float64
+	Type string
+	Data string
+}
+
+func (e CastEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{e.Time, e.Type, e.Data})
+}
+
+func (e *CastEvent) UnmarshalJSON(data []byte) error {
+	var fields []json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if len(fields) != 3 {
+		return fmt.Errorf("asciicast event has %d fields, expected 3", len(fields))
+	}
+	if err := json.Unmarshal(fields[0], &e.Time); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(fields[1], &e.Type); err != nil {
+		return err
+	}
+	return json.Unmarshal(fields[2], &e.Data)
+}
+
+// Recorder writes the frames rendered by a driver as an asciicast v2
+// recording, as played by asciinema. Each frame redraws the previous one in
+// place, the way the TUI does.
+type Recorder struct {
+	Now func() time.Time
+
+	Width, Height int // of the terminal, until the driver learns its size
+
+	mu      sync.Mutex
+	enc     *json.Encoder
+	start   time.Time
+	started bool
+	last    string
+	err     error
+}
+
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{
+		Now: time.Now,
+
+		Width:  80,
+		Height: 24,
+
+		enc: json.NewEncoder(w),
+	}
+}
+
+// Frame records view, unless it is unchanged.
+func (r *Recorder) Frame(view string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if view == r.last {
+		return
+	}
+
+	var data strings.Builder
+	if r.started {
+		// return to the start of the previous frame and clear it
+		if lines := strings.Count(r.last, "\n"); lines > 0 {
+			fmt.Fprintf(&data, "\x1b[%dA", lines)
+		}
+		data.WriteString("\r\x1b[J")
+	}
+	data.WriteString(strings.ReplaceAll(view, "\n", "\r\n"))
+
+	r.last = view
+	r.event("o", data.String())
+}
+
+// Resize records a change in the size of the terminal.
+func (r *Recorder) Resize(width, height int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if width <= 0 || height <= 0 || (width == r.Width && height == r.Height) {
+		return
+	}
+
+	r.Width, r.Height = width, height
+	if r.started {
+		r.event("r", fmt.Sprintf("%dx%d", width, height))
+	}
+}
+
+// Err returns the first error writing the recording.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.err
+}
+
+// event writes an event, preceded by the header for the first one, which is
+// delayed until then for the size of the terminal to be known.
+func (r *Recorder) event(typ, data string) {
+	if r.err != nil {
+		return
+	}
+
+	now := r.Now()
+	if !r.started {
+		r.started = true
+		r.start = now
+
+		r.err = r.enc.Encode(CastHeader{
+			Version:   2,
+			Width:     r.Width,
+			Height:    r.Height,
+			Timestamp: now.Unix(),
+		})
+		if r.err != nil {
+			return
+		}
+	}
+
+	r.err = r.enc.Encode(CastEvent{
+		Time: now.Sub(r.start).Seconds(),
+		Type: typ,
+		Data: data,
+	})
+}
+
+// ReadCast reads the header and events of an asciicast v2 recording.
+func ReadCast(rd io.Reader) (CastHeader, []CastEvent, error) {
+	scanner := bufio.NewScanner(rd)
+	scanner.Buffer(nil, 16<<20)
+
+	var header CastHeader
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return header, nil, err
+		}
+		return header, nil, errors.New("empty asciicast recording")
+	}
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return header, nil, fmt.Errorf("reading asciicast header: %w", err)
+	}
+	if header.Version != 2 {
+		return header, nil, fmt.Errorf("unsupported asciicast version %d, expected 2", header.Version)
+	}
+
+	var events []CastEvent
+	for line := 2; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var event CastEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return header, nil, fmt.Errorf("reading asciicast line %d: %w", line, err)
+		}
+		events = append(events, event)
+	}
+	return header, events, scanner.Err()
+}
+
+// Replay writes the output events to w at the pace they were recorded.
+func Replay(ctx context.Context, w io.Writer, events []CastEvent) error {
+	start := time.Now()
+	for _, event := range events {
+		if event.Type != "o" {
+			continue
+		}
+
+		at := start.Add(time.Duration(event.Time * float64(time.Second)))
+		if wait := time.Until(at); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if _, err := io.WriteString(w, event.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
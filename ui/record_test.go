@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	var buf bytes.Buffer
+
+	start := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	now := start
+
+	rec := NewRecorder(&buf)
+	rec.Now = func() time.Time { return now }
+
+	rec.Resize(100, 30) // before the first frame, part of the header
+	rec.Frame("one\n")
+	rec.Frame("one\n") // unchanged
+
+	now = now.Add(1500 * time.Millisecond)
+	rec.Frame("one\ntwo\n")
+
+	now = now.Add(time.Second)
+	rec.Resize(0, 0) // unknown
+	rec.Resize(120, 40)
+	require.NoError(t, rec.Err())
+
+	require.Equal(t, strings.Join([]string{
+		`{"version":2,"width":100,"height":30,"timestamp":1704207845}`,
+		`[0,"o","one\r\n"]`,
+		`[1.5,"o","\u001b[1A\r\u001b[Jone\r\ntwo\r\n"]`,
+		`[2.5,"r","120x40"]`,
+	}, "\n")+"\n", buf.String())
+
+	header, events, err := ReadCast(&buf)
+	require.NoError(t, err)
+	require.Equal(t, CastHeader{Version: 2, Width: 100, Height: 30, Timestamp: 1704207845}, header)
+	require.Equal(t, []CastEvent{
+		{Time: 0, Type: "o", Data: "one\r\n"},
+		{Time: 1.5, Type: "o", Data: "\x1b[1A\r\x1b[Jone\r\ntwo\r\n"},
+		{Time: 2.5, Type: "r", Data: "120x40"},
+	}, events)
+}
+
+func TestReadCast(t *testing.T) {
+	_, _, err := ReadCast(strings.NewReader(`{"version":1}` + "\n"))
+	require.ErrorContains(t, err, "unsupported asciicast version 1")
+
+	_, _, err = ReadCast(strings.NewReader(`{"version":2}` + "\n" + `[0,"o"]` + "\n"))
+	require.ErrorContains(t, err, "reading asciicast line 2: asciicast event has 2 fields, expected 3")
+}
+
+func TestReplay(t *testing.T) {
+	events := []CastEvent{
+		{Time: 0, Type: "o", Data: "one"},
+		{Time: 0.05, Type: "r", Data: "100x30"},
+		{Time: 0.1, Type: "o", Data: "two"},
+	}
+
+	var buf bytes.Buffer
+	start := time.Now()
+	require.NoError(t, Replay(context.Background(), &buf, events))
+	require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	require.Equal(t, "onetwo", buf.String())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, Replay(ctx, &buf, events), context.Canceled)
+}
+
+func TestDriverRecord(t *testing.T) {
+	var buf bytes.Buffer
+
+	drv := &Driver{}
+	drv.Record(NewRecorder(&buf))
+	drv.Replace("s3cr3t", "[redacted]")
+
+	mdl := MessageLines{"token: s3cr3t"}
+	drv.models = []tea.Model{mdl}
+	drv.active = mdl
+	drv.View()
+
+	_, events, err := ReadCast(&buf)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Contains(t, events[0].Data, "token: [redacted]")
+	require.NotContains(t, events[0].Data, "s3cr3t")
+}